@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidAdjustment when the adjustment type is unknown, percent and amount are empty or negative
+var ErrInvalidAdjustment = errors.New("invalid adjustment")
+
+// Adjustment types
+const (
+	AdjustmentTypeDiscount  string = "discount"
+	AdjustmentTypeSurcharge string = "surcharge"
+)
+
+// Adjustment define a named document discount or surcharge, as percent or fixed amount
+// Adjustments apply in order, before tax on the total without tax or after tax on the total with tax
+type Adjustment struct {
+	Name     string `json:"name,omitempty"`      // ex Loyalty discount, PROMO10, Small order surcharge
+	Type     string `json:"type,omitempty"`      // discount or surcharge, default discount
+	Percent  string `json:"percent,omitempty"`   // Adjustment in percent ex 10
+	Amount   string `json:"amount,omitempty"`    // Adjustment in amount ex 4.90
+	AfterTax bool   `json:"after_tax,omitempty"` // Apply on the total with tax, tax is not affected
+
+	_percent decimal.Decimal
+	_amount  decimal.Decimal
+}
+
+// AdjustmentStep represent an applied adjustment and the running total after it
+type AdjustmentStep struct {
+	Adjustment *Adjustment     `json:"adjustment"`
+	Amount     decimal.Decimal `json:"amount"` // Signed amount, negative for a discount
+	Total      decimal.Decimal `json:"total"`  // Total after the adjustment
+}
+
+// Prepare convert strings to decimal
+func (a *Adjustment) Prepare() error {
+	if len(a.Type) == 0 {
+		a.Type = AdjustmentTypeDiscount
+	}
+
+	if a.Type != AdjustmentTypeDiscount && a.Type != AdjustmentTypeSurcharge {
+		return ErrInvalidAdjustment
+	}
+
+	if len(a.Percent) == 0 && len(a.Amount) == 0 {
+		return ErrInvalidAdjustment
+	}
+
+	// Percent
+	if len(a.Percent) > 0 {
+		percent, err := decimal.NewFromString(a.Percent)
+		if err != nil {
+			return err
+		}
+		if percent.IsNegative() {
+			return ErrInvalidAdjustment
+		}
+		a._percent = percent
+	}
+
+	// Amount
+	if len(a.Amount) > 0 {
+		amount, err := decimal.NewFromString(a.Amount)
+		if err != nil {
+			return err
+		}
+		if amount.IsNegative() {
+			return ErrInvalidAdjustment
+		}
+		a._amount = amount
+	}
+
+	return nil
+}
+
+// apply return the signed adjustment amount on total
+func (a *Adjustment) apply(total decimal.Decimal, r *rounding) decimal.Decimal {
+	amount := a._amount
+	if len(a.Amount) == 0 {
+		amount = r.round(total.Mul(a._percent.Div(decimal.NewFromFloat(100))))
+	}
+
+	if a.Type == AdjustmentTypeSurcharge {
+		return amount
+	}
+
+	return amount.Neg()
+}
+
+// title return the adjustment row title
+func (a *Adjustment) title(options *Options) string {
+	title := a.Name
+	if len(title) == 0 {
+		title = options.TextTotalDiscount
+		if a.Type == AdjustmentTypeSurcharge {
+			title = options.TextTotalSurcharge
+		}
+	}
+
+	if len(a.Amount) == 0 && len(a.Percent) > 0 {
+		title = fmt.Sprintf("%s (%s %%)", title, a.Percent)
+	}
+
+	return title
+}
+
+// adjustmentSteps apply adjustments matching afterTax in order, starting from total
+func (doc *Document) adjustmentSteps(total decimal.Decimal, afterTax bool) []*AdjustmentStep {
+	r := doc.rounding()
+	steps := make([]*AdjustmentStep, 0, len(doc.Adjustments))
+
+	for _, adjustment := range doc.Adjustments {
+		if adjustment.AfterTax != afterTax {
+			continue
+		}
+
+		amount := adjustment.apply(total, r)
+		total = total.Add(amount)
+		steps = append(steps, &AdjustmentStep{
+			Adjustment: adjustment,
+			Amount:     amount,
+			Total:      total,
+		})
+	}
+
+	return steps
+}
+
+// BeforeTaxAdjustments return the adjustments applied on the total without tax, after the document discount
+func (doc *Document) BeforeTaxAdjustments() []*AdjustmentStep {
+	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount().Sub(doc.documentDiscountAmount())
+	return doc.adjustmentSteps(total, false)
+}
+
+// AfterTaxAdjustments return the adjustments applied on the total with tax
+func (doc *Document) AfterTaxAdjustments() []*AdjustmentStep {
+	return doc.adjustmentSteps(doc.TotalWithoutTax().Add(doc.Tax()), true)
+}
+
+// documentAdjustmentAmount return the amount removed from the total without tax
+// by the document discount and the before tax adjustments, negative when surcharges prevail
+func (doc *Document) documentAdjustmentAmount() decimal.Decimal {
+	amount := doc.documentDiscountAmount()
+	for _, step := range doc.BeforeTaxAdjustments() {
+		amount = amount.Sub(step.Amount)
+	}
+
+	return amount
+}
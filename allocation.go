@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrDiscountOnZeroTotal when a document discount is applied to a document without amount
+var ErrDiscountOnZeroTotal = errors.New("document discount on a zero total")
+
+// ErrDiscountExceedsTotal when the document discount is greater than the document total
+var ErrDiscountExceedsTotal = errors.New("document discount exceeds total")
+
+// documentDiscountAmount return the document discount as an amount
+func (doc *Document) documentDiscountAmount() decimal.Decimal {
+	if doc.Discount == nil {
+		return decimal.NewFromFloat(0)
+	}
+
+	discountType, discountNumber := doc.Discount.getDiscount()
+	if discountType == DiscountTypeAmount {
+		return discountNumber
+	}
+
+	// Percent
+	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	return doc.rounding().round(total.Mul(discountNumber.Div(decimal.NewFromFloat(100))))
+}
+
+// DiscountAllocation return the share of the document discount allocated to each priced line, in items order
+// Bundles priced by their children are replaced by their children
+// Before tax adjustments are included, a surcharge being a negative share
+// The discount is distributed proportionally to the items total without tax,
+// the rounding remainder is allocated to the largest item
+func (doc *Document) DiscountAllocation() ([]decimal.Decimal, error) {
+	items := doc.pricedItems()
+	allocation := make([]decimal.Decimal, len(items))
+	for index := range allocation {
+		allocation[index] = decimal.NewFromFloat(0)
+	}
+
+	discount := doc.documentAdjustmentAmount()
+	if discount.IsZero() {
+		return allocation, nil
+	}
+
+	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
+	if total.IsZero() {
+		return allocation, ErrDiscountOnZeroTotal
+	}
+
+	// A surcharge can exceed the total, a discount cannot
+	if discount.Sign() == total.Sign() && discount.Abs().GreaterThan(total.Abs()) {
+		return allocation, ErrDiscountExceedsTotal
+	}
+
+	r := doc.rounding()
+	allocated := decimal.NewFromFloat(0)
+	largest := 0
+
+	for index, item := range items {
+		itemTotal := item.TotalWithoutTaxAndWithDiscount()
+		allocation[index] = r.roundLine(discount.Mul(itemTotal).Div(total))
+		allocated = allocated.Add(allocation[index])
+
+		if itemTotal.Abs().GreaterThan(items[largest].TotalWithoutTaxAndWithDiscount().Abs()) {
+			largest = index
+		}
+	}
+
+	// Remainder correction
+	allocation[largest] = allocation[largest].Add(discount.Sub(allocated))
+
+	return allocation, nil
+}
@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"image/jpeg"
-	"time"
 
 	"github.com/boombuler/barcode"
 	"github.com/boombuler/barcode/code128"
 	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
 )
 
 // Build pdf document from data provided
@@ -54,11 +54,15 @@ func (doc *Document) Build() (*fpdf.Fpdf, error) {
 	// Appenf document metas (ref & version)
 	doc.appendMetas()
 
+	// Append expired watermark
+	doc.appendExpiredWatermark()
+	doc.appendPaidStamp()
+
 	// Append company contact to doc
 	companyBottom := doc.Company.appendCompanyContactToDoc(doc)
 
-	// Append customer contact to doc
-	customerBottom := doc.Customer.appendCustomerContactToDoc(doc)
+	// Append customer (or supplier) contact to doc
+	customerBottom := doc.recipient().appendCustomerContactToDoc(doc)
 
 	if customerBottom > companyBottom {
 		doc.pdf.SetXY(10, customerBottom)
@@ -69,26 +73,68 @@ func (doc *Document) Build() (*fpdf.Fpdf, error) {
 	// Append description
 	doc.appendDescription()
 
-	// Append items
-	doc.appendItems()
+	// Delivery notes only list goods, without prices nor totals
+	if doc.Type == DeliveryNote {
+		// Append items
+		doc.appendDeliveryItems()
 
-	// Check page height (total bloc height = 30, 45 when doc discount)
-	offset := doc.pdf.GetY() + 30
-	if doc.Discount != nil {
-		offset += 15
-	}
-	if offset > MaxPageHeight {
-		doc.pdf.AddPage()
-	}
+		// Check page height (signature bloc height = 48)
+		if doc.pdf.GetY()+48 > MaxPageHeight {
+			doc.pdf.AddPage()
+		}
 
-	// Append notes
-	doc.appendNotes()
+		// Append notes
+		doc.appendNotes()
 
-	// Append total
-	doc.appendTotal()
+		// Append signature
+		doc.appendSignature()
+	} else {
+		// Append items
+		doc.appendItems()
+
+		// Check page height (total bloc height = 30, 45 when doc discount, 10 per adjustment)
+		offset := doc.pdf.GetY() + 30
+		if doc.Discount != nil {
+			offset += 15
+		}
+		offset += float64(10 * (len(doc.Adjustments) + len(doc.Charges)))
+		if len(doc.Payments) > 0 {
+			offset += 20
+		}
+		if doc.ExchangeRate != nil {
+			offset += 25
+		}
+		if offset > MaxPageHeight {
+			doc.pdf.AddPage()
+		}
 
-	// Append payment term
-	doc.appendPaymentTerm()
+		// Append notes
+		doc.appendNotes()
+
+		// Append total
+		doc.appendTotal()
+
+		// Append tax summary
+		doc.appendTaxSummary()
+
+		// Append payments history
+		doc.appendPaymentsHistory()
+
+		// Append tax exemption mentions
+		doc.appendTaxMentions()
+
+		// Append payment term
+		doc.appendPaymentTerm()
+
+		// Append payment terms details
+		doc.appendPaymentTerms()
+
+		// Append timesheet annex
+		doc.appendTimesheet()
+	}
+
+	// Append pro forma or credit note notice
+	doc.appendNotice()
 
 	// Append js to autoprint if AutoPrint == true
 	if doc.Options.AutoPrint {
@@ -132,14 +178,93 @@ func (doc *Document) appendMetas() {
 	}
 
 	// Append date
-	date := time.Now().Format("02/01/2006")
-	if len(doc.Date) > 0 {
-		date = doc.Date
-	}
-	dateString := fmt.Sprintf("%s: %s", doc.Options.TextDateTitle, date)
+	dateString := fmt.Sprintf("%s: %s", doc.Options.TextDateTitle, doc.formatDate(doc.date()))
 	doc.pdf.SetXY(120, BaseMarginTop+19)
 	doc.pdf.SetFont(doc.Options.Font, "", 11)
 	doc.pdf.CellFormat(80, 4, doc.encodeString(dateString), "0", 0, "R", false, 0, "")
+
+	// Append additional metas (validity, credited invoice, payment...)
+	metaY := BaseMarginTop + 23
+	for _, meta := range doc.additionalMetas() {
+		doc.pdf.SetXY(120, metaY)
+		doc.pdf.SetFont(doc.Options.Font, "", 11)
+		doc.pdf.CellFormat(80, 4, doc.encodeString(meta), "0", 0, "R", false, 0, "")
+		metaY += 4
+	}
+}
+
+// appendExpiredWatermark to document when its validity date is over
+func (doc *Document) appendExpiredWatermark() {
+	if !doc.Options.ExpiredWatermark || !doc.IsExpired() {
+		return
+	}
+
+	currentX, currentY := doc.pdf.GetXY()
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 80)
+	doc.pdf.SetTextColor(
+		doc.Options.ExpiredWatermarkColor[0],
+		doc.Options.ExpiredWatermarkColor[1],
+		doc.Options.ExpiredWatermarkColor[2],
+	)
+	doc.pdf.SetAlpha(0.3, "Normal")
+
+	// Draw rotated text in the middle of the page
+	doc.pdf.TransformBegin()
+	doc.pdf.TransformRotate(45, 105, 148)
+	doc.pdf.SetXY(5, 138)
+	doc.pdf.CellFormat(200, 20, doc.encodeString(doc.Options.TextExpiredWatermark), "0", 0, "C", false, 0, "")
+	doc.pdf.TransformEnd()
+
+	// Reset alpha, font and position
+	doc.pdf.SetAlpha(1, "Normal")
+	doc.pdf.SetFont(doc.Options.Font, "", 15)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetXY(currentX, currentY)
+}
+
+// appendPaidStamp to document when its balance is settled
+func (doc *Document) appendPaidStamp() {
+	if !doc.IsPaid() {
+		return
+	}
+
+	currentX, currentY := doc.pdf.GetXY()
+
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 28)
+	doc.pdf.SetTextColor(
+		doc.Options.PaidStampColor[0],
+		doc.Options.PaidStampColor[1],
+		doc.Options.PaidStampColor[2],
+	)
+	doc.pdf.SetDrawColor(
+		doc.Options.PaidStampColor[0],
+		doc.Options.PaidStampColor[1],
+		doc.Options.PaidStampColor[2],
+	)
+	doc.pdf.SetLineWidth(1)
+
+	// Draw rotated stamp on the right of the metas
+	doc.pdf.TransformBegin()
+	doc.pdf.TransformRotate(15, 165, 50)
+	doc.pdf.SetXY(140, 43)
+	doc.pdf.CellFormat(50, 14, doc.encodeString(doc.Options.TextPaidStamp), "1", 0, "C", false, 0, "")
+	doc.pdf.TransformEnd()
+
+	// Reset line, font and position
+	doc.pdf.SetDrawColor(0, 0, 0)
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.SetFont(doc.Options.Font, "", 15)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetXY(currentX, currentY)
 }
 
 // appendDescription to document
@@ -162,91 +287,21 @@ func (doc *Document) drawsTableTitles() {
 	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
 	doc.pdf.Rect(10, doc.pdf.GetY(), 190, 6, "F")
 
-	// Name
-	doc.pdf.SetX(ItemColNameOffset)
-	doc.pdf.CellFormat(
-		ItemColUnitPriceOffset-ItemColNameOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsNameTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Unit price
-	doc.pdf.SetX(ItemColUnitPriceOffset)
-	doc.pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsUnitCostTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Quantity
-	doc.pdf.SetX(ItemColQuantityOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsQuantityTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Total HT
-	doc.pdf.SetX(ItemColTotalHTOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsTotalHTTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Tax - removed from items header
-
-	// Discount
-	doc.pdf.SetX(ItemColDiscountOffset)
-	doc.pdf.CellFormat(
-		ItemColTotalTTCOffset-ItemColDiscountOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsDiscountTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// TOTAL TTC
-	doc.pdf.SetX(ItemColTotalTTCOffset)
-	doc.pdf.CellFormat(
-		190-ItemColTotalTTCOffset,
-		6,
-		doc.encodeString(doc.Options.TextItemsTotalTTCTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	// Columns
+	for _, column := range doc.itemColumns() {
+		doc.pdf.SetX(column.x)
+		doc.pdf.CellFormat(
+			column.width,
+			6,
+			doc.encodeString(column.title(doc.Options)),
+			"0",
+			0,
+			column.align(),
+			false,
+			0,
+			"",
+		)
+	}
 }
 
 // appendItems to document
@@ -257,26 +312,32 @@ func (doc *Document) appendItems() {
 	doc.pdf.SetY(doc.pdf.GetY() + 8)
 	doc.pdf.SetFont(doc.Options.Font, "", 11)
 
-	for i := 0; i < len(doc.Items); i++ {
-		item := doc.Items[i]
-
-		// Check item tax
-		if item.Tax == nil {
-			item.Tax = doc.DefaultTax
+	for _, group := range doc.GroupTotals() {
+		// Group header
+		if len(group.Name) > 0 {
+			doc.appendItemsGroupHeader(group)
 		}
 
-		// Append to pdf
-		item.appendColTo(doc.Options, doc)
+		for _, item := range group.lines() {
+			// Append to pdf
+			item.appendColTo(doc.Options, doc)
 
-		if doc.pdf.GetY() > MaxPageHeight {
-			// Add page
-			doc.pdf.AddPage()
-			doc.drawsTableTitles()
-			doc.pdf.SetFont(doc.Options.Font, "", 11)
+			if doc.pdf.GetY() > MaxPageHeight {
+				// Add page
+				doc.pdf.AddPage()
+				doc.drawsTableTitles()
+				doc.pdf.SetFont(doc.Options.Font, "", 11)
+			}
+
+			doc.pdf.SetX(10)
+			doc.pdf.SetY(doc.pdf.GetY() + 6)
 		}
 
-		doc.pdf.SetX(10)
-		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		// Group subtotal
+		if len(group.Name) > 0 {
+			doc.appendItemsGroupSubtotal(group)
+			doc.pdf.SetY(doc.pdf.GetY() + 4)
+		}
 	}
 }
 
@@ -354,7 +415,7 @@ func (doc *Document) appendTotal() {
 		)
 
 		var descString bytes.Buffer
-		_, discountAmount := doc.Discount.getDiscount()
+		discountAmount := doc.documentDiscountAmount()
 		// if discountType == DiscountTypePercent {
 		// 	descString.WriteString("-")
 		// 	descString.WriteString(discountAmount.String())
@@ -402,6 +463,16 @@ func (doc *Document) appendTotal() {
 		doc.pdf.SetY(doc.pdf.GetY() + 10)
 	}
 
+	// Draw before tax adjustments
+	for _, step := range doc.BeforeTaxAdjustments() {
+		doc.appendTotalRow(step.Adjustment.title(doc.Options), step.Amount)
+	}
+
+	// Draw charges
+	for _, charge := range doc.Charges {
+		doc.appendTotalRow(charge.Name, charge._amount)
+	}
+
 	// Draw tax title
 	doc.pdf.SetX(120)
 	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
@@ -424,12 +495,17 @@ func (doc *Document) appendTotal() {
 		"",
 	)
 
-	// Draw total with tax title
+	// Draw after tax adjustments
 	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	for _, step := range doc.AfterTaxAdjustments() {
+		doc.appendTotalRow(step.Adjustment.title(doc.Options), step.Amount)
+	}
+
+	// Draw total with tax title
 	doc.pdf.SetX(120)
 	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
 	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
-	doc.pdf.CellFormat(38, 10, doc.encodeString(doc.Options.TextTotalWithTax), "0", 0, "R", false, 0, "")
+	doc.pdf.CellFormat(38, 10, doc.encodeString(doc.totalWithTaxTitle()), "0", 0, "R", false, 0, "")
 
 	// Draw total with tax amount
 	doc.pdf.SetX(162)
@@ -446,15 +522,129 @@ func (doc *Document) appendTotal() {
 		0,
 		"",
 	)
+
+	// Draw paid and balance due
+	doc.appendPayments()
+
+	// Draw tax and total in the secondary currency
+	doc.appendConvertedTotal()
+}
+
+// appendTotalRow draws a title and amount row of the total bloc at current Y
+func (doc *Document) appendTotalRow(title string, amount decimal.Decimal) {
+	doc.appendTotalTextRow(title, doc.ac.FormatMoneyDecimal(amount))
+}
+
+// appendTotalTextRow draws a title and formatted amount row of the total bloc at current Y
+func (doc *Document) appendTotalTextRow(title string, amount string) {
+	// Draw title
+	doc.pdf.SetX(120)
+	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Rect(120, doc.pdf.GetY(), 40, 10, "F")
+	doc.pdf.CellFormat(38, 10, doc.encodeString(title), "0", 0, "R", false, 0, "")
+
+	// Draw amount
+	doc.pdf.SetX(162)
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(160, doc.pdf.GetY(), 40, 10, "F")
+	doc.pdf.CellFormat(
+		40,
+		10,
+		doc.encodeString(amount),
+		"0",
+		0,
+		"L",
+		false,
+		0,
+		"",
+	)
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+}
+
+// appendTaxSummary to document
+func (doc *Document) appendTaxSummary() {
+	if !doc.Options.ShowTaxSummary {
+		return
+	}
+
+	breakdown := doc.TaxBreakdown()
+	if len(breakdown) == 0 {
+		return
+	}
+
+	// Check page height (header + one row per rate)
+	if doc.pdf.GetY()+16+float64(len(breakdown)+1)*6 > MaxPageHeight {
+		doc.pdf.AddPage()
+	} else {
+		doc.pdf.SetY(doc.pdf.GetY() + 16)
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+
+	// Draw titles
+	doc.pdf.SetX(120)
+	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Rect(120, doc.pdf.GetY(), 80, 6, "F")
+	doc.pdf.CellFormat(20, 6, doc.encodeString(doc.Options.TextTaxSummaryRate), "0", 0, "L", false, 0, "")
+	doc.pdf.CellFormat(30, 6, doc.encodeString(doc.Options.TextTaxSummaryBase), "0", 0, "R", false, 0, "")
+	doc.pdf.CellFormat(30, 6, doc.encodeString(doc.Options.TextTaxSummaryTax), "0", 0, "R", false, 0, "")
+
+	// Draw rates
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	for _, rateTotal := range breakdown {
+		rate := doc.Options.TextTaxSummaryAmount
+		if rateTotal.Type == TaxTypePercent {
+			rate = fmt.Sprintf("%s %%", rateTotal.Percent.String())
+		}
+		if label := taxCategoryLabel(rateTotal.Category, doc.Options); len(label) > 0 {
+			rate = label
+		}
+		if len(rateTotal.Name) > 0 {
+			rate = fmt.Sprintf("%s %s", rateTotal.Name, rate)
+		}
+
+		doc.pdf.SetXY(120, doc.pdf.GetY()+6)
+		doc.pdf.Rect(120, doc.pdf.GetY(), 80, 6, "F")
+		doc.pdf.CellFormat(20, 6, doc.encodeString(rate), "0", 0, "L", false, 0, "")
+		doc.pdf.CellFormat(30, 6, doc.encodeString(doc.ac.FormatMoneyDecimal(rateTotal.Base)), "0", 0, "R", false, 0, "")
+		doc.pdf.CellFormat(30, 6, doc.encodeString(doc.ac.FormatMoneyDecimal(rateTotal.Tax)), "0", 0, "R", false, 0, "")
+	}
+}
+
+// appendTaxMentions to document
+func (doc *Document) appendTaxMentions() {
+	mentions := doc.taxMentions()
+	if len(mentions) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+
+	for _, mention := range mentions {
+		doc.pdf.SetX(BaseMargin)
+		doc.pdf.MultiCell(190, 4, doc.encodeString(mention), "0", "R", false)
+	}
 }
 
 // appendPaymentTerm to document
 func (doc *Document) appendPaymentTerm() {
-	if len(doc.PaymentTerm) > 0 {
+	// Receipts are already paid
+	if doc.Type == Receipt {
+		return
+	}
+
+	if dueDate := doc.DueDate(); !dueDate.IsZero() {
 		paymentTermString := fmt.Sprintf(
 			"%s: %s",
 			doc.encodeString(doc.Options.TextPaymentTermTitle),
-			doc.encodeString(doc.PaymentTerm),
+			doc.encodeString(doc.formatDate(dueDate)),
 		)
 		doc.pdf.SetY(doc.pdf.GetY() + 15)
 
@@ -464,6 +654,30 @@ func (doc *Document) appendPaymentTerm() {
 	}
 }
 
+// appendNotice to document
+func (doc *Document) appendNotice() {
+	notice := doc.notice()
+	if len(notice) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 15)
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.MultiCell(190, 4, doc.encodeString(notice), "0", "C", false)
+
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
+
 // generateBarcode generates a Code 128 barcode image
 func (doc *Document) generateBarcode(content string) ([]byte, error) {
 	if len(content) == 0 {
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidBundlePricing when the bundle pricing of an item is unknown
+// or a bundle priced by its children has a quantity or a discount
+var ErrInvalidBundlePricing = errors.New("invalid bundle pricing")
+
+// Bundle pricing, how the amounts of an item with children are computed
+const (
+	// BundlePricingParent price the bundle with the parent unit cost, children are included at zero
+	BundlePricingParent string = "parent"
+	// BundlePricingChildren price each child, the parent line shows the sum of its children
+	BundlePricingChildren string = "children"
+)
+
+// BundleIndent is the indentation of child items names in mm
+const BundleIndent float64 = 5
+
+// rollsUp return true if the item amounts are the sum of its children
+func (i *Item) rollsUp() bool {
+	return len(i.Children) > 0 && i.BundlePricing == BundlePricingChildren
+}
+
+// pricedItems return the lines of the item counted in totals, itself or its priced descendants
+func (i *Item) pricedItems() []*Item {
+	if !i.rollsUp() {
+		return []*Item{i}
+	}
+
+	var items []*Item
+	for _, child := range i.Children {
+		items = append(items, child.pricedItems()...)
+	}
+
+	return items
+}
+
+// withChildren return the item followed by its descendants, in rendering order
+func (i *Item) withChildren() []*Item {
+	items := []*Item{i}
+	for _, child := range i.Children {
+		items = append(items, child.withChildren()...)
+	}
+
+	return items
+}
+
+// rolledUpTotals return the totals without tax before item discount and with tax of the priced lines of the item
+func (i *Item) rolledUpTotals() (decimal.Decimal, decimal.Decimal) {
+	totalWithoutTax := decimal.NewFromFloat(0)
+	totalWithTax := decimal.NewFromFloat(0)
+
+	for _, item := range i.pricedItems() {
+		totalWithoutTax = totalWithoutTax.Add(item.TotalWithoutTaxAndWithoutDiscount())
+		totalWithTax = totalWithTax.Add(item.TotalWithTaxAndDiscount())
+	}
+
+	return totalWithoutTax, totalWithTax
+}
+
+// pricedItems return the lines of the document counted in totals, each amount is counted once
+func (doc *Document) pricedItems() []*Item {
+	var items []*Item
+	for _, item := range doc.Items {
+		items = append(items, item.pricedItems()...)
+	}
+
+	return items
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidCharge when the charge name or amount is empty, or the amount is negative
+var ErrInvalidCharge = errors.New("invalid charge")
+
+// Charge define a non-item fee of the document, ex shipping or handling
+// Charges are added to the total without tax, after the document discount and adjustments
+type Charge struct {
+	Name   string `json:"name,omitempty" validate:"required"`
+	Amount string `json:"amount,omitempty"` // Charge amount without tax ex 9.90
+	Tax    *Tax   `json:"tax,omitempty"`    // Untaxed when empty
+
+	_amount decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (c *Charge) Prepare() error {
+	if len(c.Name) == 0 || len(c.Amount) == 0 {
+		return ErrInvalidCharge
+	}
+
+	amount, err := decimal.NewFromString(c.Amount)
+	if err != nil {
+		return err
+	}
+	if amount.IsNegative() {
+		return ErrInvalidCharge
+	}
+	c._amount = amount
+
+	// Tax
+	if c.Tax != nil {
+		if err := c.Tax.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// taxes return the charge taxes
+func (c *Charge) taxes() []*Tax {
+	if c.Tax == nil {
+		return nil
+	}
+
+	return []*Tax{c.Tax}
+}
+
+// ChargesTotal return the total of charges without tax
+func (doc *Document) ChargesTotal() decimal.Decimal {
+	total := decimal.NewFromFloat(0)
+
+	for _, charge := range doc.Charges {
+		total = total.Add(charge._amount)
+	}
+
+	return total
+}
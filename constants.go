@@ -10,6 +10,18 @@ const (
 	// DeliveryNote define the "delievry note" document type
 	DeliveryNote string = "DELIVERY_NOTE"
 
+	// CreditNote define the "credit note" document type
+	CreditNote string = "CREDIT_NOTE"
+
+	// ProForma define the "pro forma invoice" document type
+	ProForma string = "PRO_FORMA"
+
+	// Receipt define the "payment receipt" document type
+	Receipt string = "RECEIPT"
+
+	// PurchaseOrder define the "purchase order" document type
+	PurchaseOrder string = "PURCHASE_ORDER"
+
 	// BaseMargin define base margin used in documents
 	BaseMargin float64 = 10
 
@@ -47,6 +59,21 @@ const (
 	ItemColTotalTTCOffset float64 = 175
 )
 
+// Delivery note cols offsets
+const (
+	// DeliveryColNameOffset ...
+	DeliveryColNameOffset float64 = 10
+
+	// DeliveryColQuantityOffset ...
+	DeliveryColQuantityOffset float64 = 115
+
+	// DeliveryColUnitOffset ...
+	DeliveryColUnitOffset float64 = 135
+
+	// DeliveryColStatusOffset ...
+	DeliveryColStatusOffset float64 = 155
+)
+
 var (
 	// BaseTextFontSize define the base font size for text in document
 	BaseTextFontSize float64 = 10
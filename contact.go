@@ -15,6 +15,7 @@ type Contact struct {
 	Logo           []byte   `json:"logo,omitempty"` // Logo byte array
 	Address        *Address `json:"address,omitempty"`
 	Phone          string   `json:"phone,omitempty"`
+	TaxID          string   `json:"tax_id,omitempty"` // VAT number
 	AddtionnalInfo []string `json:"additional_info,omitempty"`
 }
 
@@ -72,6 +73,10 @@ func (c *Contact) appendContactTODoc(
 		totalHeight += 5 // Phone height
 	}
 
+	if c.TaxID != "" {
+		totalHeight += 5 // Tax ID height
+	}
+
 	if c.Address != nil {
 		addrHeight := 17
 		if len(c.Address.Address2) > 0 {
@@ -100,6 +105,18 @@ func (c *Contact) appendContactTODoc(
 		doc.pdf.CellFormat(80, 5, doc.encodeString(fmt.Sprintf("%s: %s", doc.Options.TextPhoneTitle, c.Phone)), "0", 0, "L", false, 0, "")
 	}
 
+	if c.TaxID != "" {
+		// Below the phone, or the name when there is no phone
+		offset := 10.0
+		if c.Phone != "" {
+			offset = 5
+		}
+
+		doc.pdf.SetXY(x, doc.pdf.GetY()+offset)
+		doc.pdf.SetFont(doc.Options.Font, "", 13)
+		doc.pdf.CellFormat(80, 5, doc.encodeString(fmt.Sprintf("%s: %s", doc.Options.TextTaxIDTitle, c.TaxID)), "0", 0, "L", false, 0, "")
+	}
+
 	if c.Address != nil {
 		// Set address - match Title Invoice width
 		doc.pdf.SetFont(doc.Options.Font, "", 13)
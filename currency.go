@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/leekchan/accounting"
+)
+
+// Currency define the formatting of an ISO 4217 currency
+type Currency struct {
+	Code        string `json:"code"`             // ISO 4217 code ex EUR
+	Symbol      string `json:"symbol,omitempty"` // Code when empty, ex for symbols missing from core fonts
+	MinorUnits  int    `json:"minor_units"`      // Decimal digits ex 2 for EUR, 0 for JPY, 3 for KWD
+	SymbolAfter bool   `json:"symbol_after"`     // Symbol placed after the amount ex 12 345 €
+}
+
+// currenciesMutex guards the currency table against concurrent registrations
+var currenciesMutex sync.RWMutex
+
+// currencies is the built-in ISO 4217 currency table
+var currencies = map[string]*Currency{
+	"AUD": {Code: "AUD", Symbol: "A$", MinorUnits: 2},
+	"BHD": {Code: "BHD", MinorUnits: 3},
+	"CAD": {Code: "CAD", Symbol: "CA$", MinorUnits: 2},
+	"CHF": {Code: "CHF", MinorUnits: 2},
+	"CNY": {Code: "CNY", MinorUnits: 2},
+	"CZK": {Code: "CZK", MinorUnits: 2, SymbolAfter: true},
+	"DKK": {Code: "DKK", Symbol: "kr.", MinorUnits: 2, SymbolAfter: true},
+	"EUR": {Code: "EUR", Symbol: "€", MinorUnits: 2, SymbolAfter: true},
+	"GBP": {Code: "GBP", Symbol: "£", MinorUnits: 2},
+	"INR": {Code: "INR", MinorUnits: 2},
+	"JPY": {Code: "JPY", Symbol: "¥", MinorUnits: 0},
+	"KWD": {Code: "KWD", MinorUnits: 3},
+	"NOK": {Code: "NOK", Symbol: "kr", MinorUnits: 2, SymbolAfter: true},
+	"PLN": {Code: "PLN", MinorUnits: 2, SymbolAfter: true},
+	"SEK": {Code: "SEK", Symbol: "kr", MinorUnits: 2, SymbolAfter: true},
+	"USD": {Code: "USD", Symbol: "$", MinorUnits: 2},
+	"VND": {Code: "VND", MinorUnits: 0, SymbolAfter: true},
+}
+
+// RegisterCurrency add or replace a currency of the currency table, it is safe for concurrent use
+func RegisterCurrency(currency *Currency) {
+	currenciesMutex.Lock()
+	defer currenciesMutex.Unlock()
+
+	currencies[strings.ToUpper(currency.Code)] = currency
+}
+
+// LookupCurrency return the currency of ISO 4217 code
+func LookupCurrency(code string) (*Currency, bool) {
+	currenciesMutex.RLock()
+	defer currenciesMutex.RUnlock()
+
+	currency, ok := currencies[strings.ToUpper(code)]
+	return currency, ok
+}
+
+// symbol return the currency symbol, its code when empty
+func (c *Currency) symbol() string {
+	if len(c.Symbol) == 0 {
+		return c.Code
+	}
+
+	return c.Symbol
+}
+
+// formats return the positive, negative and zero formats placing the symbol
+func (c *Currency) formats() (string, string, string) {
+	if c.SymbolAfter {
+		return "%v %s", "-%v %s", "0 %s"
+	}
+
+	return "%s %v", "%s -%v", "%s 0"
+}
+
+// NoMinorUnits is the precision forcing amounts without decimals, a zero precision uses the currency minor units
+const NoMinorUnits int = -1
+
+// currencyMinorUnits return the decimal digits of currency code, precision overrides the currency table when set
+func currencyMinorUnits(code string, precision int) int {
+	if precision == NoMinorUnits {
+		return 0
+	}
+
+	if precision > 0 {
+		return precision
+	}
+
+	if currency, ok := LookupCurrency(code); ok {
+		return currency.MinorUnits
+	}
+
+	return fallbackCurrency.MinorUnits
+}
+
+// fallbackCurrency formats the amounts of documents without currency or with a currency missing from the table
+var fallbackCurrency = &Currency{Symbol: "€", MinorUnits: 2}
+
+// currencyAccounting return the formatter of currency code, options set by the caller override the currency table
+func currencyAccounting(code string, options *Options) accounting.Accounting {
+	currency, ok := LookupCurrency(code)
+	if !ok {
+		currency = fallbackCurrency
+	}
+
+	format, formatNegative, formatZero := currency.formats()
+	ac := accounting.Accounting{
+		Symbol:         currency.symbol(),
+		Precision:      currencyMinorUnits(code, options.CurrencyPrecision),
+		Thousand:       options.CurrencyThousand,
+		Decimal:        options.CurrencyDecimal,
+		Format:         format,
+		FormatNegative: formatNegative,
+		FormatZero:     formatZero,
+	}
+
+	if len(options.CurrencySymbol) > 0 {
+		ac.Symbol = options.CurrencySymbol
+	}
+	if len(options.Format) > 0 {
+		ac.Format = options.Format
+	}
+	if len(options.FormatNegative) > 0 {
+		ac.FormatNegative = options.FormatNegative
+	}
+	if len(options.FormatZero) > 0 {
+		ac.FormatZero = options.FormatZero
+	}
+
+	return ac
+}
+
+// prepareAccounting build the document money formatter from its currency and options
+func (doc *Document) prepareAccounting() {
+	doc.ac = currencyAccounting(doc.Currency, doc.Options)
+}
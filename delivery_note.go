@@ -0,0 +1,237 @@
+package generator
+
+import (
+	"fmt"
+)
+
+// drawsDeliveryTableTitles in document
+// Delivery notes have no price, tax nor total columns
+func (doc *Document) drawsDeliveryTableTitles() {
+	// Draw table titles
+	doc.pdf.SetX(10)
+	doc.pdf.SetY(doc.pdf.GetY() + 5)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", 11)
+
+	// Draw rec
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	doc.pdf.Rect(10, doc.pdf.GetY(), 190, 6, "F")
+
+	// Name
+	doc.pdf.SetX(DeliveryColNameOffset)
+	doc.pdf.CellFormat(
+		DeliveryColQuantityOffset-DeliveryColNameOffset,
+		6,
+		doc.encodeString(doc.Options.TextItemsNameTitle),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+
+	// Quantity
+	doc.pdf.SetX(DeliveryColQuantityOffset)
+	doc.pdf.CellFormat(
+		DeliveryColUnitOffset-DeliveryColQuantityOffset,
+		6,
+		doc.encodeString(doc.Options.TextItemsQuantityTitle),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+
+	// Unit
+	doc.pdf.SetX(DeliveryColUnitOffset)
+	doc.pdf.CellFormat(
+		DeliveryColStatusOffset-DeliveryColUnitOffset,
+		6,
+		doc.encodeString(doc.Options.TextItemsUnitTitle),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+
+	// Delivered / backordered
+	doc.pdf.SetX(DeliveryColStatusOffset)
+	doc.pdf.CellFormat(
+		200-DeliveryColStatusOffset,
+		6,
+		doc.encodeString(doc.Options.TextItemsDeliveredTitle),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+}
+
+// appendDeliveryItems to document
+func (doc *Document) appendDeliveryItems() {
+	doc.drawsDeliveryTableTitles()
+
+	doc.pdf.SetX(10)
+	doc.pdf.SetY(doc.pdf.GetY() + 8)
+	doc.pdf.SetFont(doc.Options.Font, "", 11)
+
+	// Bundle components ship with their parent and are listed under it
+	for _, item := range doc.Items {
+		for _, line := range item.withChildren() {
+			// Append to pdf
+			line.appendDeliveryColTo(doc)
+
+			if doc.pdf.GetY() > MaxPageHeight {
+				// Add page
+				doc.pdf.AddPage()
+				doc.drawsDeliveryTableTitles()
+				doc.pdf.SetFont(doc.Options.Font, "", 11)
+			}
+
+			doc.pdf.SetX(10)
+			doc.pdf.SetY(doc.pdf.GetY() + 6)
+		}
+	}
+}
+
+// appendDeliveryColTo document doc
+func (i *Item) appendDeliveryColTo(doc *Document) {
+	// Get base Y (top of line)
+	baseY := doc.pdf.GetY() + 5
+
+	// Bundle components are indented under their parent
+	indent := float64(i._depth) * BundleIndent
+	x := DeliveryColNameOffset + indent
+	width := DeliveryColQuantityOffset - DeliveryColNameOffset - indent
+
+	// Name
+	doc.pdf.SetXY(x, baseY)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.MultiCell(
+		width,
+		4,
+		doc.encodeString(i.Name),
+		"",
+		"",
+		false,
+	)
+
+	// Description
+	if len(i.Description) > 0 {
+		doc.pdf.SetXY(x, doc.pdf.GetY()+1)
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.SetTextColor(
+			doc.Options.GreyTextColor[0],
+			doc.Options.GreyTextColor[1],
+			doc.Options.GreyTextColor[2],
+		)
+
+		doc.pdf.MultiCell(
+			width,
+			3,
+			doc.encodeString(i.Description),
+			"",
+			"",
+			false,
+		)
+
+		// Reset font
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
+		)
+	}
+
+	// Compute line height
+	colHeight := doc.pdf.GetY() - baseY
+
+	// Quantity
+	doc.pdf.SetXY(DeliveryColQuantityOffset, baseY)
+	doc.pdf.CellFormat(
+		DeliveryColUnitOffset-DeliveryColQuantityOffset,
+		colHeight,
+		doc.encodeString(i._quantity.String()),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+
+	// Unit
+	doc.pdf.SetX(DeliveryColUnitOffset)
+	doc.pdf.CellFormat(
+		DeliveryColStatusOffset-DeliveryColUnitOffset,
+		colHeight,
+		doc.encodeString(i.Unit),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+
+	// Delivered / backordered
+	delivered := i._quantity.Sub(i._backordered)
+	doc.pdf.SetX(DeliveryColStatusOffset)
+	doc.pdf.CellFormat(
+		200-DeliveryColStatusOffset,
+		colHeight,
+		doc.encodeString(fmt.Sprintf("%s / %s", delivered.String(), i._backordered.String())),
+		"0",
+		0,
+		"",
+		false,
+		0,
+		"",
+	)
+
+	// Set Y for next line
+	doc.pdf.SetY(baseY + colHeight)
+}
+
+// appendSignature to document
+// Draws the box the recipient fills when receiving the goods
+func (doc *Document) appendSignature() {
+	baseY := doc.pdf.GetY() + 10
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+
+	// Draw title
+	doc.pdf.SetXY(120, baseY)
+	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Rect(120, baseY, 80, 8, "F")
+	doc.pdf.CellFormat(80, 8, doc.encodeString(doc.Options.TextSignatureTitle), "0", 0, "C", false, 0, "")
+
+	// Draw box
+	doc.pdf.SetDrawColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Rect(120, baseY+8, 80, 30, "D")
+
+	// Draw fields
+	fields := []string{
+		doc.Options.TextSignatureName,
+		doc.Options.TextSignatureDate,
+		doc.Options.TextSignatureSignature,
+	}
+	for index, field := range fields {
+		doc.pdf.SetXY(122, baseY+9+float64(index)*9)
+		doc.pdf.CellFormat(76, 8, doc.encodeString(fmt.Sprintf("%s:", field)), "B", 0, "LB", false, 0, "")
+	}
+
+	doc.pdf.SetY(baseY + 38)
+}
@@ -1,6 +1,10 @@
 package generator
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/go-pdf/fpdf"
 	"github.com/leekchan/accounting"
 )
@@ -10,24 +14,37 @@ type Document struct {
 	pdf *fpdf.Fpdf
 	ac  accounting.Accounting
 
-	Options        *Options      `json:"options,omitempty"`
-	Header         *HeaderFooter `json:"header,omitempty"`
-	Footer         *HeaderFooter `json:"footer,omitempty"`
-	Type           string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION"`
-	Ref            string        `json:"ref,omitempty" validate:"required,min=1,max=32"`
-	Version        string        `json:"version,omitempty" validate:"max=32"`
-	ClientRef      string        `json:"client_ref,omitempty" validate:"max=64"`
-	Description    string        `json:"description,omitempty" validate:"max=1024"`
-	Notes          string        `json:"notes,omitempty"`
-	BarCode        string        `json:"barcode,omitempty"`
-	Company        *Contact      `json:"company,omitempty" validate:"required"`
-	Customer       *Contact      `json:"customer,omitempty" validate:"required"`
-	Items          []*Item       `json:"items,omitempty"`
-	Date           string        `json:"date,omitempty"`
-	ValidityDate   string        `json:"validity_date,omitempty"`
-	PaymentTerm    string        `json:"payment_term,omitempty"`
-	DefaultTax     *Tax          `json:"default_tax,omitempty"`
-	Discount       *Discount     `json:"discount,omitempty"`
+	Options       *Options      `json:"options,omitempty"`
+	Header        *HeaderFooter `json:"header,omitempty"`
+	Footer        *HeaderFooter `json:"footer,omitempty"`
+	Type          string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION CREDIT_NOTE PRO_FORMA RECEIPT PURCHASE_ORDER"`
+	Ref           string        `json:"ref,omitempty" validate:"required,min=1,max=32"`
+	OriginalRef   string        `json:"original_ref,omitempty" validate:"required_if=Type CREDIT_NOTE,max=32"`
+	OriginalDate  time.Time     `json:"original_date,omitzero"`
+	Version       string        `json:"version,omitempty" validate:"max=32"`
+	ClientRef     string        `json:"client_ref,omitempty" validate:"max=64"`
+	Description   string        `json:"description,omitempty" validate:"max=1024"`
+	Notes         string        `json:"notes,omitempty"`
+	BarCode       string        `json:"barcode,omitempty"`
+	Company       *Contact      `json:"company,omitempty" validate:"required"`
+	Customer      *Contact      `json:"customer,omitempty" validate:"required_unless=Type PURCHASE_ORDER"`
+	Supplier      *Contact      `json:"supplier,omitempty" validate:"required_if=Type PURCHASE_ORDER"`
+	Items         []*Item       `json:"items,omitempty"`
+	Date          time.Time     `json:"date,omitzero"`
+	ValidityDate  time.Time     `json:"validity_date,omitzero"`
+	PaymentTerm   time.Time     `json:"payment_term,omitzero"` // Due date, computed from the payment terms net days when empty
+	PaymentTerms  *PaymentTerms `json:"payment_terms,omitempty"`
+	PaymentDate   time.Time     `json:"payment_date,omitzero" validate:"required_if=Type RECEIPT"`
+	PaymentMethod string        `json:"payment_method,omitempty" validate:"required_if=Type RECEIPT,max=64"`
+	DefaultTax    *Tax          `json:"default_tax,omitempty"`
+	DefaultTaxes  []*Tax        `json:"default_taxes,omitempty"`
+	TaxInclusive  bool          `json:"tax_inclusive,omitempty"` // Items unit costs include taxes
+	Discount      *Discount     `json:"discount,omitempty"`
+	Adjustments   []*Adjustment `json:"adjustments,omitempty"`
+	Charges       []*Charge     `json:"charges,omitempty" validate:"dive"`
+	Payments      []*Payment    `json:"payments,omitempty"`
+	Currency      string        `json:"currency,omitempty" validate:"omitempty,len=3,uppercase"` // ISO 4217 code ex USD
+	ExchangeRate  *ExchangeRate `json:"exchange_rate,omitempty"`                                 // Secondary currency of the tax and total
 }
 
 // Pdf returns the underlying *fpdf.Fpdf used to build document
@@ -51,13 +68,172 @@ func (doc *Document) encodeString(str string) string {
 
 // typeAsString return the document type as string
 func (d *Document) typeAsString() string {
-	if d.Type == Invoice {
+	switch d.Type {
+	case Invoice:
 		return d.Options.TextTypeInvoice
-	}
-
-	if d.Type == Quotation {
+	case Quotation:
 		return d.Options.TextTypeQuotation
+	case CreditNote:
+		return d.Options.TextTypeCreditNote
+	case ProForma:
+		return d.Options.TextTypeProForma
+	case Receipt:
+		return d.Options.TextTypeReceipt
+	case PurchaseOrder:
+		return d.Options.TextTypePurchaseOrder
 	}
 
 	return d.Options.TextTypeDeliveryNote
 }
+
+// additionalMetas return the meta lines displayed under the document date
+func (d *Document) additionalMetas() []string {
+	var metas []string
+
+	if !d.ValidityDate.IsZero() {
+		metas = append(metas, fmt.Sprintf("%s: %s", d.Options.TextValidityDateTitle, d.formatDate(d.ValidityDate)))
+	}
+
+	switch d.Type {
+	case CreditNote:
+		if !d.OriginalDate.IsZero() {
+			metas = append(metas, fmt.Sprintf("%s: %s (%s)", d.Options.TextOriginalRefTitle, d.OriginalRef, d.formatDate(d.OriginalDate)))
+		} else {
+			metas = append(metas, fmt.Sprintf("%s: %s", d.Options.TextOriginalRefTitle, d.OriginalRef))
+		}
+	case Receipt:
+		metas = append(metas, fmt.Sprintf("%s: %s (%s)", d.Options.TextPaymentDateTitle, d.formatDate(d.PaymentDate), d.PaymentMethod))
+	}
+
+	return metas
+}
+
+// recipient return the contact the document is addressed to
+// Purchase orders are sent to a supplier, other documents to a customer
+func (d *Document) recipient() *Contact {
+	if d.Type == PurchaseOrder {
+		return d.Supplier
+	}
+
+	return d.Customer
+}
+
+// totalWithTaxTitle return the title of the grand total row
+// Credit notes display their total as a credit and receipts as an amount paid
+func (d *Document) totalWithTaxTitle() string {
+	switch d.Type {
+	case CreditNote:
+		return d.Options.TextTotalCredit
+	case Receipt:
+		return d.Options.TextTotalPaid
+	}
+
+	return d.Options.TextTotalWithTax
+}
+
+// notice return the notice printed at the bottom of the document
+// Pro forma invoices are not tax invoices and credit note amounts are credits
+func (d *Document) notice() string {
+	switch d.Type {
+	case ProForma:
+		return d.Options.TextProFormaNotice
+	case CreditNote:
+		return d.Options.TextCreditNoteNotice
+	}
+
+	return ""
+}
+
+// date return the document date, today when not set
+func (d *Document) date() time.Time {
+	if d.Date.IsZero() {
+		return time.Now()
+	}
+
+	return d.Date
+}
+
+// DueDate return the payment due date
+// An explicit payment term wins, else it is computed from the document date and payment terms net days
+func (d *Document) DueDate() time.Time {
+	if !d.PaymentTerm.IsZero() {
+		return d.PaymentTerm
+	}
+
+	if d.PaymentTerms != nil && d.PaymentTerms.NetDays > 0 {
+		return d.date().AddDate(0, 0, d.PaymentTerms.NetDays)
+	}
+
+	return time.Time{}
+}
+
+// IsExpired return true if the document validity date is over
+func (d *Document) IsExpired() bool {
+	if d.ValidityDate.IsZero() {
+		return false
+	}
+
+	// The document stays valid during the whole validity day
+	return time.Now().After(truncateDay(d.ValidityDate).AddDate(0, 0, 1))
+}
+
+// layoutNameTokens are the Go layout tokens of month and weekday names, full names first
+var layoutNameTokens = []string{"January", "Jan", "Monday", "Mon"}
+
+// formatDate format date using the document date format, month and day names
+// The January, Jan, Monday and Mon layout tokens are replaced with the localized names
+func (d *Document) formatDate(date time.Time) string {
+	var formatted strings.Builder
+
+	layout := d.Options.DateFormat
+	for {
+		index, token := nextNameToken(layout)
+		if index < 0 {
+			formatted.WriteString(date.Format(layout))
+			break
+		}
+
+		formatted.WriteString(date.Format(layout[:index]))
+		formatted.WriteString(d.dateName(date, token))
+		layout = layout[index+len(token):]
+	}
+
+	return formatted.String()
+}
+
+// nextNameToken return the index of the first month or weekday name token of layout and the token, -1 when none
+// Go layouts read any Jan as a month token and any Mon as a weekday token
+func nextNameToken(layout string) (int, string) {
+	index, token := -1, ""
+	for _, candidate := range layoutNameTokens {
+		if i := strings.Index(layout, candidate); i >= 0 && (index < 0 || i < index) {
+			index, token = i, candidate
+		}
+	}
+
+	return index, token
+}
+
+// dateName return the localized month or weekday of date for a January, Jan, Monday or Mon layout token
+func (d *Document) dateName(date time.Time, token string) string {
+	names, count, index := d.Options.MonthShortNames, 12, int(date.Month())-1
+	switch token {
+	case "January":
+		names = d.Options.MonthNames
+	case "Monday":
+		names, count, index = d.Options.DayNames, 7, int(date.Weekday())
+	case "Mon":
+		names, count, index = d.Options.DayShortNames, 7, int(date.Weekday())
+	}
+
+	if len(names) != count {
+		return date.Format(token)
+	}
+
+	return names[index]
+}
+
+// truncateDay return the start of the day of date
+func truncateDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
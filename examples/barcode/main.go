@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	generator "github.com/tuanhuu3264/tuan-invoice"
 )
@@ -13,7 +14,7 @@ func main() {
 		CurrencySymbol:    "VND",
 		CurrencyThousand:  ".",
 		CurrencyDecimal:   ",",
-		CurrencyPrecision: 0,
+		CurrencyPrecision: generator.NoMinorUnits,
 		Format:            "%v %s",
 		FormatNegative:    "- %v %s",
 		FormatZero:        "0 %s",
@@ -58,8 +59,8 @@ func main() {
 	doc.SetDescription("Sample invoice with barcode")
 	doc.SetNotes("This invoice includes a Code 128 barcode for easy scanning and tracking.")
 
-	doc.SetDate("15/01/2024")
-	doc.SetPaymentTerm("15/02/2024")
+	doc.SetDate(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC))
+	doc.SetPaymentTerm(time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC))
 
 	doc.SetCompany(&generator.Contact{
 		Name: "Sample Company Ltd",
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	generator "github.com/tuanhuu3264/tuan-invoice"
 )
@@ -52,10 +53,10 @@ func main() {
 	}
 	doc1.SetRef("INV-001")
 	doc1.SetVersion("1.0")
-	doc1.SetDate("15/01/2024")
+	doc1.SetDate(time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC))
 	doc1.SetDescription("First invoice description")
 	doc1.SetNotes("Khi mở vui lòng quay video")
-	doc1.SetPaymentTerm("30 days")
+	doc1.SetNetDays(30)
 	doc1.SetBarCode("EZ210166800VN")
 
 	// Read logo file
@@ -104,10 +105,10 @@ func main() {
 	}
 	doc2.SetRef("INV-002")
 	doc2.SetVersion("1.0")
-	doc2.SetDate("16/01/2024")
+	doc2.SetDate(time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC))
 	doc2.SetDescription("Second invoice description")
 	doc2.SetNotes("Khi mở vui lòng quay video")
-	doc2.SetPaymentTerm("15 days")
+	doc2.SetNetDays(15)
 	doc2.SetBarCode("EZ210166800VN")
 
 	// Set company info (reuse the same logo)
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidExchangeRate when an exchange rate has no currency, a rate not strictly positive
+// or converts to the document currency
+var ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+
+// ErrMissingCurrency when an exchange rate is set on a document without currency
+var ErrMissingCurrency = errors.New("missing document currency")
+
+// ExchangeRate define the conversion of the document totals to a secondary currency
+// ex the tax amount in the local currency at the official rate
+type ExchangeRate struct {
+	Currency  string    `json:"currency,omitempty" validate:"required,len=3,uppercase"` // ISO 4217 code ex VND
+	Rate      string    `json:"rate,omitempty"`                                         // Units of Currency for one unit of the document currency
+	Source    string    `json:"source,omitempty"`                                       // ex State Bank of Vietnam
+	Date      time.Time `json:"date,omitzero"`
+	Symbol    string    `json:"symbol,omitempty"`                      // Currency table symbol or code when empty
+	Precision int       `json:"precision,omitempty" validate:"min=-1"` // Currency table minor units when 0, NoMinorUnits for none
+
+	_rate decimal.Decimal
+	_ac   accounting.Accounting
+	_mode string // Document rounding mode, half up when empty
+}
+
+// Prepare convert strings to decimal
+func (e *ExchangeRate) Prepare() error {
+	rate, err := decimal.NewFromString(e.Rate)
+	if err != nil {
+		return err
+	}
+	if !rate.IsPositive() {
+		return ErrInvalidExchangeRate
+	}
+	e._rate = rate
+
+	return nil
+}
+
+// Convert return amount in the secondary currency, rounded to its precision with the document rounding mode
+// The rate must have been prepared, see Prepare
+func (e *ExchangeRate) Convert(amount decimal.Decimal) decimal.Decimal {
+	r := &rounding{precision: int32(currencyMinorUnits(e.Currency, e.Precision)), mode: e._mode}
+
+	return r.round(amount.Mul(e._rate))
+}
+
+// accounting return the formatter of the secondary currency, separators are the document ones
+func (e *ExchangeRate) accounting(options *Options) accounting.Accounting {
+	secondary := *options
+	secondary.CurrencySymbol = e.Symbol
+	secondary.CurrencyPrecision = e.Precision
+	secondary.Format, secondary.FormatNegative, secondary.FormatZero = "", "", ""
+
+	// Unknown currencies are shown with their code
+	if _, ok := LookupCurrency(e.Currency); !ok && len(e.Symbol) == 0 {
+		secondary.CurrencySymbol = e.Currency
+	}
+
+	return currencyAccounting(e.Currency, &secondary)
+}
+
+// prepareExchangeRate check the exchange rate against the document currency
+func (d *Document) prepareExchangeRate() error {
+	if d.ExchangeRate == nil {
+		return nil
+	}
+
+	if len(d.Currency) == 0 {
+		return ErrMissingCurrency
+	}
+
+	if d.ExchangeRate.Currency == d.Currency {
+		return ErrInvalidExchangeRate
+	}
+
+	d.ExchangeRate._ac = d.ExchangeRate.accounting(d.Options)
+	d.ExchangeRate._mode = d.Options.RoundingMode
+
+	return d.ExchangeRate.Prepare()
+}
+
+// ConvertedTax return the document tax in the exchange rate currency
+func (d *Document) ConvertedTax() decimal.Decimal {
+	return d.ExchangeRate.Convert(d.Tax())
+}
+
+// ConvertedTotalWithTax return the document total with tax in the exchange rate currency
+func (d *Document) ConvertedTotalWithTax() decimal.Decimal {
+	return d.ExchangeRate.Convert(d.TotalWithTax())
+}
+
+// exchangeRateMention return the applied rate ex 1 USD = 25 000 VND (State Bank of Vietnam, 02/03/2021)
+func (d *Document) exchangeRateMention() string {
+	mention := fmt.Sprintf(
+		"%s: 1 %s = %s %s",
+		d.Options.TextExchangeRate,
+		d.Currency,
+		d.ExchangeRate._rate.String(),
+		d.ExchangeRate.Currency,
+	)
+
+	var origin string
+	if len(d.ExchangeRate.Source) > 0 {
+		origin = d.ExchangeRate.Source
+	}
+	if !d.ExchangeRate.Date.IsZero() {
+		if len(origin) > 0 {
+			origin += ", "
+		}
+		origin += d.formatDate(d.ExchangeRate.Date)
+	}
+	if len(origin) > 0 {
+		mention = fmt.Sprintf("%s (%s)", mention, origin)
+	}
+
+	return mention
+}
+
+// convertedTotalRows return the titles and formatted amounts of the tax and total in the secondary currency
+func (d *Document) convertedTotalRows() [][2]string {
+	ac := d.ExchangeRate._ac
+
+	return [][2]string{
+		{
+			fmt.Sprintf("%s (%s)", d.Options.TextTotalTax, d.ExchangeRate.Currency),
+			ac.FormatMoneyDecimal(d.ConvertedTax()),
+		},
+		{
+			fmt.Sprintf("%s (%s)", d.totalWithTaxTitle(), d.ExchangeRate.Currency),
+			ac.FormatMoneyDecimal(d.ConvertedTotalWithTax()),
+		},
+	}
+}
+
+// appendConvertedTotal draws the tax and total with tax in the secondary currency and the applied rate
+func (doc *Document) appendConvertedTotal() {
+	if doc.ExchangeRate == nil {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	for _, row := range doc.convertedTotalRows() {
+		doc.appendTotalTextRow(row[0], row[1])
+	}
+
+	// Applied rate
+	doc.pdf.SetX(120)
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.CellFormat(80, 5, doc.encodeString(doc.exchangeRateMention()), "0", 0, "R", false, 0, "")
+
+	// Reset font
+	doc.pdf.SetFont(doc.Options.Font, "", LargeTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+
+	// Keep Y 10 above the bottom of the bloc, as after the total with tax
+	doc.pdf.SetY(doc.pdf.GetY() - 5)
+}
@@ -1,4 +1,4 @@
-// Package generator allows you to easily generate invoices, delivery notes and quotations in GoLang.
+// Package generator allows you to easily generate invoices, credit notes, receipts, purchase orders, delivery notes and quotations in GoLang.
 package generator
 
 import (
@@ -6,16 +6,37 @@ import (
 
 	"github.com/creasty/defaults"
 	"github.com/go-pdf/fpdf"
-	"github.com/leekchan/accounting"
 )
 
 var ErrInvalidDocumentType = errors.New("invalid document type")
 
+// documentTypes lists the document types accepted by New
+var documentTypes = []string{
+	Invoice,
+	Quotation,
+	DeliveryNote,
+	CreditNote,
+	ProForma,
+	Receipt,
+	PurchaseOrder,
+}
+
+// isValidDocumentType return true if docType is a known document type
+func isValidDocumentType(docType string) bool {
+	for _, t := range documentTypes {
+		if t == docType {
+			return true
+		}
+	}
+
+	return false
+}
+
 // New return a new documents with provided types and defaults
 func New(docType string, options *Options) (*Document, error) {
 	_ = defaults.Set(options)
 
-	if docType != Invoice && docType != Quotation && docType != DeliveryNote {
+	if !isValidDocumentType(docType) {
 		return nil, ErrInvalidDocumentType
 	}
 
@@ -31,15 +52,7 @@ func New(docType string, options *Options) (*Document, error) {
 	doc.BarCode = doc.Options.BarCode
 
 	// Prepare accounting
-	doc.ac = accounting.Accounting{
-		Symbol:         doc.Options.CurrencySymbol,
-		Precision:      doc.Options.CurrencyPrecision,
-		Thousand:       doc.Options.CurrencyThousand,
-		Decimal:        doc.Options.CurrencyDecimal,
-		Format:         doc.Options.Format,
-		FormatNegative: doc.Options.FormatNegative,
-		FormatZero:     doc.Options.FormatZero,
-	}
+	doc.prepareAccounting()
 
 	return doc, nil
 }
@@ -1,11 +1,33 @@
 package generator
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
 )
 
+// newTestDocument return a document of docType with its required ref, company and customer set
+func newTestDocument(t *testing.T, docType string, options *Options) *Document {
+	doc, err := New(docType, options)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	doc.SetRef("ref")
+	doc.SetCompany(&Contact{Name: "Company"})
+	doc.SetCustomer(&Contact{Name: "Customer"})
+
+	return doc
+}
+
 func TestNewWithInvalidType(t *testing.T) {
 	_, err := New("INVALID", &Options{})
 
@@ -16,6 +38,989 @@ func TestNewWithInvalidType(t *testing.T) {
 	t.Fatalf("expected ErrInvalidDocumentType, got %v", err)
 }
 
+func TestCreditNote(t *testing.T) {
+	doc := newTestDocument(t, CreditNote, &Options{})
+	doc.AppendItem(&Item{Name: "Returned item", UnitCost: "10", Quantity: "1"})
+
+	// Credit notes reference the credited invoice
+	var validationErrors validator.ValidationErrors
+	if err := doc.Validate(); !errors.As(err, &validationErrors) || validationErrors[0].Field() != "OriginalRef" {
+		t.Fatalf("expected OriginalRef to be required, got %v", err)
+	}
+
+	doc.SetOriginalRef("INV-001")
+	doc.SetOriginalDate(time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC))
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if metas := doc.additionalMetas(); len(metas) != 1 || metas[0] != "Original invoice: INV-001 (02/03/2021)" {
+		t.Fatalf("unexpected metas %v", metas)
+	}
+
+	// Amounts are shown as credits
+	if doc.totalWithTaxTitle() != doc.Options.TextTotalCredit || doc.notice() != doc.Options.TextCreditNoteNotice {
+		t.Fatalf("expected credit title and notice, got %q and %q", doc.totalWithTaxTitle(), doc.notice())
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Invoices do not need an original ref
+	invoice := newTestDocument(t, Invoice, &Options{})
+	if err := invoice.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if len(invoice.notice()) > 0 {
+		t.Fatalf("expected no notice on invoices, got %q", invoice.notice())
+	}
+}
+
+func TestDocumentTypesRequiredFields(t *testing.T) {
+	paymentDate := time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name    string
+		docType string
+		setup   func(doc *Document)
+		field   string // Field reported as missing, empty when valid
+	}{
+		{"pro forma", ProForma, func(doc *Document) {}, ""},
+		{"receipt without payment date", Receipt, func(doc *Document) { doc.SetPaymentMethod("Card") }, "PaymentDate"},
+		{"receipt without payment method", Receipt, func(doc *Document) { doc.SetPaymentDate(paymentDate) }, "PaymentMethod"},
+		{"receipt", Receipt, func(doc *Document) { doc.SetPaymentDate(paymentDate).SetPaymentMethod("Card") }, ""},
+		{"purchase order without supplier", PurchaseOrder, func(doc *Document) {}, "Supplier"},
+		{"purchase order without customer", PurchaseOrder, func(doc *Document) {
+			doc.SetSupplier(&Contact{Name: "Supplier"})
+			doc.Customer = nil
+		}, ""},
+	} {
+		doc := newTestDocument(t, test.docType, &Options{})
+		test.setup(doc)
+
+		err := doc.Validate()
+		if len(test.field) == 0 {
+			if err != nil {
+				t.Errorf("%s: got error %v", test.name, err)
+			}
+			continue
+		}
+
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) || validationErrors[0].Field() != test.field {
+			t.Errorf("%s: expected %s to be required, got %v", test.name, test.field, err)
+		}
+	}
+}
+
+func TestDeliveryNoteBackordered(t *testing.T) {
+	for backordered, expected := range map[string]error{
+		"":   nil,
+		"0":  nil,
+		"1":  nil,
+		"2":  nil,
+		"3":  ErrInvalidBackordered,
+		"-1": ErrInvalidBackordered,
+	} {
+		// Delivery notes items have no unit cost
+		doc := newTestDocument(t, DeliveryNote, &Options{})
+		doc.AppendItem(&Item{Name: "Pallet", Quantity: "2", Backordered: backordered})
+
+		if err := doc.Validate(); !errors.Is(err, expected) {
+			t.Errorf("backordered %q: expected %v, got %v", backordered, expected, err)
+		}
+	}
+}
+
+func TestTaxBreakdown(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "25", Quantity: "2", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "C", UnitCost: "30", Quantity: "1", Tax: &Tax{Percent: "10"}})
+	doc.AppendItem(&Item{Name: "D", UnitCost: "10", Quantity: "1"})
+	doc.AppendItem(&Item{Name: "E", UnitCost: "20", Quantity: "1", Tax: &Tax{Amount: "3"}})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Rates ascending, fixed amounts last
+	expected := []struct {
+		taxType string
+		percent string
+		base    string
+		tax     string
+	}{
+		{TaxTypePercent, "0", "10", "0"},
+		{TaxTypePercent, "10", "30", "3"},
+		{TaxTypePercent, "20", "150", "30"},
+		{TaxTypeAmount, "0", "20", "3"},
+	}
+
+	breakdown := doc.TaxBreakdown()
+	if len(breakdown) != len(expected) {
+		t.Fatalf("expected %d rates, got %d", len(expected), len(breakdown))
+	}
+
+	for index, rate := range expected {
+		got := breakdown[index]
+		if got.Type != rate.taxType ||
+			!got.Percent.Equal(decimal.RequireFromString(rate.percent)) ||
+			!got.Base.Equal(decimal.RequireFromString(rate.base)) ||
+			!got.Tax.Equal(decimal.RequireFromString(rate.tax)) {
+			t.Errorf("rate %d: expected %s %s%% %s / %s, got %s %s%% %s / %s", index, rate.taxType, rate.percent, rate.base, rate.tax, got.Type, got.Percent, got.Base, got.Tax)
+		}
+	}
+
+	if !doc.Tax().Equal(decimal.NewFromFloat(36)) {
+		t.Fatalf("expected tax 36, got %s", doc.Tax())
+	}
+}
+
+func TestCompoundTaxes(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		taxes []*Tax
+		tax   string
+	}{
+		{"single", []*Tax{{Name: "GST", Percent: "5"}}, "5"},
+		{"stacked", []*Tax{{Name: "GST", Percent: "5"}, {Name: "QST", Percent: "9.975"}}, "14.98"},
+		{"compound", []*Tax{{Name: "GST", Percent: "5"}, {Name: "QST", Percent: "9.975", Compound: true}}, "15.47"},
+		{"compound on fixed amount", []*Tax{{Name: "Eco", Amount: "2"}, {Name: "VAT", Percent: "10", Compound: true}}, "12.2"},
+		{"first compound has no previous tax", []*Tax{{Name: "VAT", Percent: "10", Compound: true}}, "10"},
+	} {
+		doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+		doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Taxes: test.taxes})
+
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("%s: got error %v", test.name, err)
+		}
+
+		if !doc.Tax().Equal(decimal.RequireFromString(test.tax)) {
+			t.Errorf("%s: expected tax %s, got %s", test.name, test.tax, doc.Tax())
+		}
+
+		// Each named tax is reported on its own
+		if breakdown := doc.TaxBreakdown(); len(breakdown) != len(test.taxes) {
+			t.Errorf("%s: expected %d breakdown lines, got %d", test.name, len(test.taxes), len(breakdown))
+		}
+	}
+}
+
+func TestTaxInclusiveNetFromGross(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		gross string
+		taxes []*Tax
+		net   string
+	}{
+		{"untaxed", "50", nil, "50"},
+		{"percent", "120", []*Tax{{Percent: "20"}}, "100"},
+		{"fixed amount", "13", []*Tax{{Amount: "3"}}, "10"},
+		{"stacked", "114.975", []*Tax{{Percent: "5"}, {Percent: "9.975"}}, "100"},
+		{"compound on fixed amount", "112.2", []*Tax{{Amount: "2"}, {Percent: "10", Compound: true}}, "100"},
+	} {
+		doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+		doc.SetTaxInclusive(true)
+		doc.AppendItem(&Item{Name: "A", UnitCost: test.gross, Quantity: "1", Taxes: test.taxes})
+
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("%s: got error %v", test.name, err)
+		}
+
+		item := doc.Items[0]
+		if !item.TotalWithoutTaxAndWithDiscount().Equal(decimal.RequireFromString(test.net)) {
+			t.Errorf("%s: expected net %s, got %s", test.name, test.net, item.TotalWithoutTaxAndWithDiscount())
+		}
+
+		// Net plus taxes is the price the customer saw
+		if gross := decimal.RequireFromString(test.gross).Round(2); !item.TotalWithTaxAndDiscount().Equal(gross) {
+			t.Errorf("%s: expected gross %s, got %s", test.name, gross, item.TotalWithTaxAndDiscount())
+		}
+	}
+}
+
+func TestTaxCategories(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		tax      *Tax
+		taxID    string
+		expected error
+		mentions []string
+	}{
+		{"reverse charge without customer tax id", &Tax{Category: TaxCategoryReverseCharge}, "", ErrMissingCustomerTaxID, nil},
+		{"reverse charge", &Tax{Category: TaxCategoryReverseCharge, Percent: "20"}, "FR123", nil, []string{
+			"Reverse charge — Article 196 Directive 2006/112/EC.",
+			"Customer VAT number: FR123",
+		}},
+		{"exempt", &Tax{Category: TaxCategoryExempt, ReasonCode: "VATEX-EU-132"}, "", nil, []string{"VAT exempt supply. (VATEX-EU-132)"}},
+		{"zero rated with reason", &Tax{Category: TaxCategoryZeroRated, ReasonText: "Export outside the EU."}, "", nil, []string{"Export outside the EU."}},
+		{"unknown category", &Tax{Category: "unknown", Percent: "20"}, "FR123", ErrInvalidTax, nil},
+	} {
+		doc := newTestDocument(t, Invoice, &Options{})
+		doc.Customer.TaxID = test.taxID
+		doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: test.tax})
+
+		if err := doc.Validate(); !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, err)
+			continue
+		}
+		if test.expected != nil {
+			continue
+		}
+
+		// Exempted taxes are not charged, even with a rate
+		if !doc.Tax().IsZero() {
+			t.Errorf("%s: expected no tax, got %s", test.name, doc.Tax())
+		}
+
+		if mentions := doc.taxMentions(); strings.Join(mentions, "\n") != strings.Join(test.mentions, "\n") {
+			t.Errorf("%s: unexpected mentions %q", test.name, mentions)
+		}
+	}
+}
+
+func TestItemDiscountColumn(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		unitCost   string
+		discount   *Discount
+		value      string
+		equivalent string
+	}{
+		{"no discount", "200", nil, "$ 0", ""},
+		{"percent", "200", &Discount{Percent: "10"}, "10 %", "-$ 20.00"},
+		{"amount", "200", &Discount{Amount: "50"}, "$ 50.00", "-25.00 %"},
+		{"amount on free line", "0", &Discount{Amount: "5"}, "$ 5.00", ""},
+	} {
+		doc := newTestDocument(t, Invoice, &Options{})
+		doc.SetCurrency("USD")
+		doc.AppendItem(&Item{Name: "A", UnitCost: test.unitCost, Quantity: "1", Discount: test.discount})
+
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("%s: got error %v", test.name, err)
+		}
+
+		value, equivalent := doc.Items[0].discountColumn(doc)
+		if value != test.value || equivalent != test.equivalent {
+			t.Errorf("%s: expected %q / %q, got %q / %q", test.name, test.value, test.equivalent, value, equivalent)
+		}
+	}
+}
+
+func TestValidityDate(t *testing.T) {
+	date := time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	for validityDate, expected := range map[time.Time]error{
+		{}:                     nil,
+		date:                   nil,
+		date.Add(time.Hour):    nil,
+		date.AddDate(0, 0, 30): nil,
+		date.Add(-time.Second): ErrInvalidValidityDate,
+		date.AddDate(0, 0, -1): ErrInvalidValidityDate,
+	} {
+		doc := newTestDocument(t, Quotation, &Options{})
+		doc.SetDate(date)
+		doc.SetValidityDate(validityDate)
+
+		if err := doc.Validate(); !errors.Is(err, expected) {
+			t.Errorf("validity date %v: expected %v, got %v", validityDate, expected, err)
+		}
+	}
+}
+
+func TestDatesJSON(t *testing.T) {
+	var doc Document
+	if err := json.Unmarshal([]byte(`{"date":"2021-03-02T00:00:00Z"}`), &doc); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if !doc.Date.Equal(time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 2021-03-02, got %v", doc.Date)
+	}
+
+	// Dates left empty are omitted
+	encoded, err := json.Marshal(&doc)
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if strings.Contains(string(encoded), "validity_date") || strings.Contains(string(encoded), "payment_term\"") {
+		t.Fatalf("expected empty dates to be omitted, got %s", encoded)
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for _, test := range []struct {
+		name         string
+		validityDate time.Time
+		expired      bool
+	}{
+		{"no validity date", time.Time{}, false},
+		{"valid until tomorrow", today.AddDate(0, 0, 1), false},
+		{"valid during the whole validity day", today, false},
+		{"validity day ended at midnight", today.Add(-time.Second), true},
+		{"valid until yesterday", today.AddDate(0, 0, -1), true},
+	} {
+		doc := newTestDocument(t, Quotation, &Options{})
+		doc.SetValidityDate(test.validityDate)
+
+		if doc.IsExpired() != test.expired {
+			t.Errorf("%s: expected expired to be %t", test.name, test.expired)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2021, time.May, 2, 0, 0, 0, 0, time.UTC)
+	monthNames := []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}
+	monthShortNames := []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."}
+	dayNames := []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+	dayShortNames := []string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."}
+
+	for _, test := range []struct {
+		layout     string
+		names      []string
+		shortNames []string
+		days       []string
+		shortDays  []string
+		expected   string
+	}{
+		{"02/01/2006", monthNames, monthShortNames, dayNames, dayShortNames, "02/05/2021"},
+		{"January 2, 2006", nil, nil, nil, nil, "May 2, 2021"},
+		{"2 January 2006", monthNames, nil, nil, nil, "2 mai 2021"},
+		{"02 Jan 2006", nil, monthShortNames, nil, nil, "02 mai 2021"},
+		{"02 Jan 2006", monthNames, nil, nil, nil, "02 May 2021"},
+		{"January (Jan) 2006", monthNames, monthShortNames, nil, nil, "mai (mai) 2021"},
+		{"Monday 2 January 2006", monthNames, nil, dayNames, nil, "dimanche 2 mai 2021"},
+		{"Mon 2 Jan 2006", nil, monthShortNames, dayNames, dayShortNames, "dim. 2 mai 2021"},
+		{"Monday 2 January 2006", monthNames, nil, nil, dayShortNames, "Sunday 2 mai 2021"},
+	} {
+		doc := newTestDocument(t, Invoice, &Options{
+			DateFormat:      test.layout,
+			MonthNames:      test.names,
+			MonthShortNames: test.shortNames,
+			DayNames:        test.days,
+			DayShortNames:   test.shortDays,
+		})
+
+		if got := doc.formatDate(date); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.layout, test.expected, got)
+		}
+	}
+}
+
+func TestMultiDocumentWithEmptyOptions(t *testing.T) {
+	md := NewMultiDocument(&Options{ShowTaxSummary: true, ShowPaymentsHistory: true})
+
+	// Shared options are the only defaults, documents helpers read colors and texts from them
+	for _, docType := range []string{Invoice, DeliveryNote} {
+		doc := newTestDocument(t, docType, &Options{})
+		doc.AppendItem(&Item{Group: "Hardware", Name: "A", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		doc.AppendItem(&Item{Name: "Support", UnitCost: "50", TimeEntries: []*TimeEntry{{Person: "Alex", Hours: "1:30"}}})
+		doc.AppendPayment(&Payment{Method: "Card", Amount: "5"})
+		doc.SetPaymentTerms(&PaymentTerms{NetDays: 30, LateFeeRate: "10"})
+		md.AddDocument(doc)
+	}
+
+	if _, err := md.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+}
+
+func TestValidateDueDate(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{})
+	doc.SetDate(time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC))
+
+	doc.SetNetDays(30)
+	if !doc.DueDate().Equal(time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected net 30 due date, got %v", doc.DueDate())
+	}
+
+	doc.SetPaymentTerm(time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC))
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidDueDate) {
+		t.Fatalf("expected ErrInvalidDueDate, got %v", err)
+	}
+}
+
+func TestPaymentTerms(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2, CurrencySymbol: "$", Format: "%s%v"})
+	doc.SetDate(time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC))
+	doc.AppendItem(&Item{Name: "A", UnitCost: "333.33", Quantity: "1"})
+	doc.SetPaymentTerms(&PaymentTerms{NetDays: 30, DiscountPercent: "2", DiscountDays: 10, LateFeeRate: "10.50", RecoveryFee: "40"})
+
+	// An explicit payment term is not the net days due date
+	doc.SetPaymentTerm(time.Date(2021, time.April, 15, 0, 0, 0, 0, time.UTC))
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !doc.EarlyPaymentDate().Equal(time.Date(2021, time.March, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected early payment date 12/03/2021, got %v", doc.EarlyPaymentDate())
+	}
+
+	// 2 % of 333.33 rounded to the currency precision
+	if !doc.EarlyPaymentDiscount().Equal(decimal.RequireFromString("6.67")) {
+		t.Fatalf("expected early payment discount 6.67, got %s", doc.EarlyPaymentDiscount())
+	}
+
+	expected := "Payment due within 30 days, by 01/04/2021. " +
+		"A 2% discount ($6.67) applies if paid by 12/03/2021. " +
+		"Late payments bear interest at a yearly rate of 10.5%. " +
+		"A fixed recovery fee of $40.00 is due for any late payment."
+	if got := doc.paymentTermsAsString(); got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	// Without discount days there is no early payment discount
+	doc.PaymentTerms.DiscountDays = 0
+	if !doc.EarlyPaymentDate().IsZero() || !doc.EarlyPaymentDiscount().IsZero() {
+		t.Fatalf("expected no early payment discount, got %s by %v", doc.EarlyPaymentDiscount(), doc.EarlyPaymentDate())
+	}
+}
+
+func TestInvalidPaymentTerms(t *testing.T) {
+	for name, terms := range map[string]*PaymentTerms{
+		"discount days after net days": {NetDays: 10, DiscountPercent: "2", DiscountDays: 15},
+		"negative net days":            {NetDays: -1},
+		"negative discount days":       {DiscountDays: -1},
+		"negative discount percent":    {NetDays: 30, DiscountPercent: "-2", DiscountDays: 10},
+		"negative late fee rate":       {NetDays: 30, LateFeeRate: "-10"},
+		"negative recovery fee":        {NetDays: 30, RecoveryFee: "-40"},
+	} {
+		if err := terms.Prepare(); !errors.Is(err, ErrInvalidPaymentTerms) {
+			t.Errorf("%s: expected ErrInvalidPaymentTerms, got %v", name, err)
+		}
+	}
+}
+
+func TestDiscountAllocation(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "10", Quantity: "1", Tax: &Tax{Amount: "3"}})
+	doc.AppendItem(&Item{Name: "C", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "10"}})
+	doc.SetDiscount(&Discount{Amount: "10"})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	allocation, _ := doc.DiscountAllocation()
+	sum := decimal.NewFromFloat(0)
+	for _, share := range allocation {
+		sum = sum.Add(share)
+	}
+	if !sum.Equal(decimal.NewFromFloat(10)) {
+		t.Fatalf("expected allocation to sum to 10, got %s", sum)
+	}
+
+	// Fixed amount tax follows the discount: 3 * 2/3
+	if !doc.Tax().Equal(decimal.RequireFromString("4")) {
+		t.Fatalf("expected tax 4, got %s", doc.Tax())
+	}
+
+	free := newTestDocument(t, Invoice, &Options{})
+	free.AppendItem(&Item{Name: "A", UnitCost: "0", Quantity: "1"})
+	free.SetDiscount(&Discount{Amount: "5"})
+
+	if err := free.Validate(); !errors.Is(err, ErrDiscountOnZeroTotal) {
+		t.Fatalf("expected ErrDiscountOnZeroTotal, got %v", err)
+	}
+}
+
+func TestAdjustments(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendAdjustment(&Adjustment{Name: "Loyalty", Percent: "10"})
+	doc.AppendAdjustment(&Adjustment{Name: "PROMO5", Amount: "5"})
+	doc.AppendAdjustment(&Adjustment{Name: "Small order", Type: AdjustmentTypeSurcharge, Amount: "2", AfterTax: true})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	steps := doc.BeforeTaxAdjustments()
+	if len(steps) != 2 || !steps[0].Total.Equal(decimal.NewFromFloat(90)) || !steps[1].Total.Equal(decimal.NewFromFloat(85)) {
+		t.Fatalf("unexpected before tax steps %v", steps)
+	}
+
+	if !doc.TotalWithoutTax().Equal(decimal.NewFromFloat(85)) || !doc.Tax().Equal(decimal.NewFromFloat(17)) {
+		t.Fatalf("expected 85 + 17 tax, got %s + %s", doc.TotalWithoutTax(), doc.Tax())
+	}
+
+	if !doc.TotalWithTax().Equal(decimal.NewFromFloat(104)) {
+		t.Fatalf("expected total with tax 104, got %s", doc.TotalWithTax())
+	}
+
+	// The type gives the sign, amounts are not negative
+	for _, adjustment := range []*Adjustment{{Name: "A", Percent: "-10"}, {Name: "B", Amount: "-5"}, {Name: "C", Type: AdjustmentTypeSurcharge, Amount: "-2"}} {
+		if err := adjustment.Prepare(); !errors.Is(err, ErrInvalidAdjustment) {
+			t.Errorf("%s: expected ErrInvalidAdjustment, got %v", adjustment.Name, err)
+		}
+	}
+}
+
+func TestCharges(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetDiscount(&Discount{Percent: "10"})
+	doc.AppendCharge(&Charge{Name: "Shipping", Amount: "10", Tax: &Tax{Percent: "20"}})
+	doc.AppendCharge(&Charge{Name: "Handling", Amount: "5"})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Charges are not discounted
+	if !doc.TotalWithoutTax().Equal(decimal.NewFromFloat(105)) {
+		t.Fatalf("expected total without tax 105, got %s", doc.TotalWithoutTax())
+	}
+
+	if !doc.Tax().Equal(decimal.NewFromFloat(20)) {
+		t.Fatalf("expected tax 20, got %s", doc.Tax())
+	}
+
+	if !doc.TotalWithTax().Equal(decimal.NewFromFloat(125)) {
+		t.Fatalf("expected total with tax 125, got %s", doc.TotalWithTax())
+	}
+
+	// Charges are named
+	var validationErrors validator.ValidationErrors
+	doc.AppendCharge(&Charge{Amount: "3"})
+	if err := doc.Validate(); !errors.As(err, &validationErrors) || validationErrors[0].Field() != "Name" {
+		t.Fatalf("expected charge Name to be required, got %v", err)
+	}
+
+	// Refunds are not charges
+	if err := (&Charge{Name: "Shipping", Amount: "-10"}).Prepare(); !errors.Is(err, ErrInvalidCharge) {
+		t.Fatalf("expected ErrInvalidCharge, got %v", err)
+	}
+}
+
+func TestPayments(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{CurrencyPrecision: 2})
+	doc.AppendItem(&Item{Name: "A", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.AppendPayment(&Payment{Date: time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC), Method: "Card", Amount: "20"})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !doc.AmountPaid().Equal(decimal.NewFromFloat(20)) || !doc.BalanceDue().Equal(decimal.NewFromFloat(100)) {
+		t.Fatalf("expected 20 paid and 100 due, got %s and %s", doc.AmountPaid(), doc.BalanceDue())
+	}
+
+	if doc.IsPaid() {
+		t.Fatalf("expected document with balance due not to be paid")
+	}
+
+	doc.AppendPayment(&Payment{Method: "Bank transfer", Amount: "100", Reference: "TX-1"})
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !doc.IsPaid() {
+		t.Fatalf("expected settled document to be paid, balance is %s", doc.BalanceDue())
+	}
+
+	// Refunds are not payments
+	if err := (&Payment{Method: "Card", Amount: "-20"}).Prepare(); !errors.Is(err, ErrInvalidPayment) {
+		t.Fatalf("expected ErrInvalidPayment, got %v", err)
+	}
+}
+
+func TestItemColumns(t *testing.T) {
+	doc, _ := New(Invoice, &Options{ItemColumns: []*ItemColumn{
+		{Key: ItemColumnName, Weight: 3},
+		{Key: ItemColumnQuantity, Width: 30},
+		{Key: ItemColumnTotalWithTax},
+	}})
+
+	columns := doc.itemColumns()
+	if columns[0].width != 120 || columns[1].x != 130 || columns[2].x != 160 || columns[2].width != 40 {
+		t.Fatalf("unexpected layout %+v %+v %+v", columns[0], columns[1], columns[2])
+	}
+
+	doc.Options.ItemColumns = append(doc.Options.ItemColumns, &ItemColumn{Width: 10})
+	if err := validateItemColumns(doc.Options.ItemColumns); !errors.Is(err, ErrInvalidItemColumn) {
+		t.Fatalf("expected ErrInvalidItemColumn, got %v", err)
+	}
+}
+
+func TestIsValidGTIN(t *testing.T) {
+	for code, valid := range map[string]bool{
+		"4006381333931":  true,  // EAN-13
+		"036000291452":   true,  // UPC-A
+		"96385074":       true,  // EAN-8
+		"10012345000017": true,  // GTIN-14
+		"4006381333932":  false, // Wrong check digit
+		"400638133393":   false, // Wrong check digit for its length
+		"40063813339A1":  false,
+		"123":            false,
+	} {
+		if isValidGTIN(code) != valid {
+			t.Errorf("expected GTIN %s validity to be %t", code, valid)
+		}
+	}
+}
+
+func TestGroupTotals(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{})
+	doc.SetDefaultTax(&Tax{Percent: "20"})
+	doc.AppendItem(&Item{Group: "Design", Name: "Mockups", UnitCost: "500", Quantity: "2"})
+	doc.AppendItem(&Item{Group: "Development", Name: "Backend", UnitCost: "800", Quantity: "5"})
+	doc.AppendItem(&Item{Group: "Design", Name: "Logo", UnitCost: "300", Quantity: "1"})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	groups := doc.GroupTotals()
+	if len(groups) != 2 || groups[0].Name != "Design" || len(groups[0].Items) != 2 {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+
+	if !groups[0].TotalWithoutTax.Equal(decimal.NewFromFloat(1300)) || !groups[0].TotalWithTax.Equal(decimal.NewFromFloat(1560)) {
+		t.Fatalf("unexpected Design totals %s / %s", groups[0].TotalWithoutTax, groups[0].TotalWithTax)
+	}
+
+	if !groups[1].Tax.Equal(decimal.NewFromFloat(800)) {
+		t.Fatalf("unexpected Development tax %s", groups[1].Tax)
+	}
+}
+
+func TestBundleTotals(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{})
+	doc.SetDefaultTax(&Tax{Percent: "10"})
+
+	// Priced by the parent, children are included, one when without quantity
+	doc.AppendItem(&Item{Name: "Starter kit", UnitCost: "100", Quantity: "1", Children: []*Item{
+		{Name: "Cable", UnitCost: "15", Quantity: "2"},
+		{Name: "Adapter"},
+	}})
+
+	// Priced by the children
+	doc.AppendItem(&Item{Name: "Custom kit", BundlePricing: BundlePricingChildren, Children: []*Item{
+		{Name: "Router", UnitCost: "60", Quantity: "1"},
+		{Name: "Antenna", UnitCost: "20", Quantity: "2"},
+	}})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if !doc.TotalWithoutTax().Equal(decimal.NewFromFloat(200)) {
+		t.Fatalf("expected total without tax 200, got %s", doc.TotalWithoutTax())
+	}
+
+	if !doc.Tax().Equal(decimal.NewFromFloat(20)) {
+		t.Fatalf("expected tax 20, got %s", doc.Tax())
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if adapter := doc.Items[0].Children[1]; !adapter._quantity.Equal(decimal.NewFromFloat(1)) {
+		t.Fatalf("expected included adapter quantity 1, got %s", adapter._quantity)
+	}
+
+	// A bundle priced by its children has no quantity nor discount of its own
+	for _, bundle := range []*Item{
+		{Name: "Custom kit", Quantity: "2", BundlePricing: BundlePricingChildren},
+		{Name: "Custom kit", Discount: &Discount{Percent: "10"}, BundlePricing: BundlePricingChildren},
+	} {
+		bundle.Children = []*Item{{Name: "Router", UnitCost: "60", Quantity: "1"}}
+
+		doc := newTestDocument(t, Invoice, &Options{})
+		doc.AppendItem(bundle)
+		if err := doc.Validate(); !errors.Is(err, ErrInvalidBundlePricing) {
+			t.Fatalf("expected ErrInvalidBundlePricing, got %v", err)
+		}
+	}
+}
+
+func TestTimesheetItem(t *testing.T) {
+	for hours, expected := range map[string]string{"1:30": "1.5", "0:45": "0.75", "2.25": "2.25"} {
+		if got, err := parseHours(hours); err != nil || got.String() != expected {
+			t.Fatalf("expected %s to be %s hours, got %s (%v)", hours, expected, got, err)
+		}
+	}
+
+	for _, hours := range []string{"1:75", "abc", "1:5", "-2"} {
+		if _, err := parseHours(hours); !errors.Is(err, ErrInvalidHours) {
+			t.Fatalf("expected %s to be invalid, got %v", hours, err)
+		}
+	}
+
+	doc := newTestDocument(t, Invoice, &Options{})
+	doc.AppendItem(&Item{Name: "Consulting", UnitCost: "80", TimeEntries: []*TimeEntry{
+		{Person: "Alex", Hours: "1:30", Task: "T-1"},
+		{Person: "Sam", Hours: "2", Rate: "100", Task: "T-2"},
+	}})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	item := doc.Items[0]
+	if item.quantityWithUnit() != "3.50 h" {
+		t.Fatalf("expected 3.50 h, got %s", item.quantityWithUnit())
+	}
+
+	if !item.TotalWithoutTaxAndWithDiscount().Equal(decimal.NewFromFloat(320)) {
+		t.Fatalf("expected 320, got %s", item.TotalWithoutTaxAndWithDiscount())
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Thirds of hours are shown rounded and annex rows add up to the line
+	doc = newTestDocument(t, Invoice, &Options{})
+	doc.AppendItem(&Item{Name: "Support", UnitCost: "50", TimeEntries: []*TimeEntry{
+		{Person: "Alex", Hours: "0:20"},
+		{Person: "Sam", Hours: "0:20"},
+	}})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	item = doc.Items[0]
+	if item.quantityWithUnit() != "0.67 h" {
+		t.Fatalf("expected 0.67 h, got %s", item.quantityWithUnit())
+	}
+
+	rows := decimal.Zero
+	for _, entry := range item.TimeEntries {
+		if row := item.timeEntryAmount(entry); !row.Equal(decimal.RequireFromString("16.67")) {
+			t.Fatalf("expected 16.67 per entry, got %s", row)
+		}
+		rows = rows.Add(item.timeEntryAmount(entry))
+	}
+	if !item.TotalWithoutTaxAndWithDiscount().Equal(rows) {
+		t.Fatalf("expected the line to be the %s sum of the annex rows, got %s", rows, item.TotalWithoutTaxAndWithDiscount())
+	}
+}
+
+func TestPricingTiers(t *testing.T) {
+	tiers := func() []*PriceTier {
+		return []*PriceTier{{UpTo: "1000", UnitCost: "0.10"}, {UpTo: "4000", UnitCost: "0.05"}, {UnitCost: "0.01"}}
+	}
+
+	doc := newTestDocument(t, Invoice, &Options{})
+	doc.AppendItem(&Item{Name: "API calls", Quantity: "6000", Pricing: &Pricing{Model: PricingModelTiered, IncludedUnits: "1000", Tiers: tiers()}})
+	doc.AppendItem(&Item{Name: "Storage", Quantity: "6000", Pricing: &Pricing{Model: PricingModelVolume, IncludedUnits: "1000", Tiers: tiers()}})
+	doc.AppendItem(&Item{Name: "Seats", Quantity: "12", UnitCost: "5", Pricing: &Pricing{IncludedUnits: "10"}})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// 1000 included, then 1000 x 0.10 + 3000 x 0.05 + 1000 x 0.01
+	tiered := doc.Items[0]
+	if !tiered.TotalWithoutTaxAndWithDiscount().Equal(decimal.NewFromFloat(260)) {
+		t.Fatalf("expected 260, got %s", tiered.TotalWithoutTaxAndWithDiscount())
+	}
+	if len(tiered.TierBreakdown()) != 4 {
+		t.Fatalf("expected 4 tier charges, got %d", len(tiered.TierBreakdown()))
+	}
+
+	// Tier ranges count the units after the included ones
+	for index, expected := range [][2]int64{{0, 1000}, {1000, 2000}, {2000, 5000}, {5000, 0}} {
+		charge := tiered.TierBreakdown()[index]
+		if charge.From.IntPart() != expected[0] || charge.To.IntPart() != expected[1] {
+			t.Fatalf("expected tier %d from %d to %d, got %s to %s", index, expected[0], expected[1], charge.From, charge.To)
+		}
+	}
+	if lines := tiered.tierBreakdownLines(doc); !strings.HasPrefix(lines[1], "1000 - 2000: 1000 x") || !strings.HasPrefix(lines[3], "5000 +: 1000 x") {
+		t.Fatalf("unexpected tier lines %q", lines)
+	}
+
+	// 5000 billable units all priced at the last tier
+	if total := doc.Items[1].TotalWithoutTaxAndWithDiscount(); !total.Equal(decimal.NewFromFloat(50)) {
+		t.Fatalf("expected 50, got %s", total)
+	}
+
+	// 2 billable seats
+	if total := doc.Items[2].TotalWithoutTaxAndWithDiscount(); !total.Equal(decimal.NewFromFloat(10)) {
+		t.Fatalf("expected 10, got %s", total)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Last tier must price the remaining units
+	bounded := &Item{Name: "Bounded", Quantity: "1", Pricing: &Pricing{Model: PricingModelTiered, Tiers: []*PriceTier{{UpTo: "10", UnitCost: "1"}}}}
+	if err := bounded.Prepare(); !errors.Is(err, ErrInvalidPricing) {
+		t.Fatalf("expected ErrInvalidPricing, got %v", err)
+	}
+
+	// Consumed quantities are not negative
+	negative := &Item{Name: "Refund", Quantity: "-10", Pricing: &Pricing{Model: PricingModelTiered, Tiers: tiers()}}
+	if err := negative.Prepare(); !errors.Is(err, ErrInvalidPricing) {
+		t.Fatalf("expected ErrInvalidPricing, got %v", err)
+	}
+}
+
+func TestExchangeRate(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{})
+	doc.SetDefaultTax(&Tax{Percent: "10"})
+	doc.AppendItem(&Item{Name: "Service", UnitCost: "100", Quantity: "3"})
+	doc.SetExchangeRate(&ExchangeRate{Currency: "VND", Rate: "25000.5", Source: "State Bank of Vietnam", Date: time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC)})
+
+	// Exchange rate needs the document currency
+	if err := doc.Validate(); !errors.Is(err, ErrMissingCurrency) {
+		t.Fatalf("expected ErrMissingCurrency, got %v", err)
+	}
+
+	doc.SetCurrency("USD")
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if tax := doc.ConvertedTax(); !tax.Equal(decimal.NewFromFloat(750015)) {
+		t.Fatalf("expected 750015, got %s", tax)
+	}
+
+	if total := doc.ConvertedTotalWithTax(); !total.Equal(decimal.NewFromFloat(8250165)) {
+		t.Fatalf("expected 8250165, got %s", total)
+	}
+
+	mention := doc.exchangeRateMention()
+	if mention != "Exchange rate: 1 USD = 25000.5 VND (State Bank of Vietnam, 02/03/2021)" {
+		t.Fatalf("unexpected mention %q", mention)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	// Conversions follow the document rounding mode, 1000 x 0.0125 = 12.5 JPY
+	for mode, expected := range map[string]string{RoundingHalfUp: "13", RoundingHalfEven: "12"} {
+		rounded := newTestDocument(t, Invoice, &Options{RoundingMode: mode})
+		rounded.SetCurrency("USD")
+		rounded.AppendItem(&Item{Name: "Service", UnitCost: "1000", Quantity: "1"})
+		rounded.SetExchangeRate(&ExchangeRate{Currency: "JPY", Rate: "0.0125"})
+		if err := rounded.Validate(); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		if total := rounded.ConvertedTotalWithTax(); total.String() != expected {
+			t.Errorf("%s: expected %s, got %s", mode, expected, total)
+		}
+	}
+
+	// Rate must be positive
+	doc.ExchangeRate.Rate = "0"
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidExchangeRate) {
+		t.Fatalf("expected ErrInvalidExchangeRate, got %v", err)
+	}
+
+	// A prepared rate converts with the secondary currency precision, before the document is validated
+	for _, test := range []struct {
+		rate      *ExchangeRate
+		converted string
+		formatted string
+	}{
+		{&ExchangeRate{Currency: "EUR", Rate: "0.912345"}, "91.23", "91.23 €"},
+		{&ExchangeRate{Currency: "EUR", Rate: "0.912345", Precision: 3}, "91.235", "91.235 €"},
+		{&ExchangeRate{Currency: "XAF", Rate: "598.123"}, "59812.3", "XAF 59 812.30"},
+	} {
+		if err := test.rate.Prepare(); err != nil {
+			t.Fatalf("got error %v", err)
+		}
+
+		converted := test.rate.Convert(decimal.NewFromFloat(100))
+		if !converted.Equal(decimal.RequireFromString(test.converted)) {
+			t.Errorf("%s: expected %s, got %s", test.rate.Currency, test.converted, converted)
+		}
+
+		ac := test.rate.accounting(doc.Options)
+		if formatted := ac.FormatMoneyDecimal(converted); formatted != test.formatted {
+			t.Errorf("%s: expected %q, got %q", test.rate.Currency, test.formatted, formatted)
+		}
+	}
+}
+
+func TestCurrencyRegistry(t *testing.T) {
+	amount := decimal.NewFromFloat(1234.5)
+
+	for _, test := range []struct {
+		currency string
+		options  *Options
+		expected string
+	}{
+		{"EUR", &Options{}, "1 234.50 €"},
+		{"USD", &Options{}, "$ 1 234.50"},
+		{"JPY", &Options{}, "¥ 1 235"},
+		{"KWD", &Options{}, "KWD 1 234.500"},
+		{"EUR", &Options{CurrencySymbol: "EUR", Format: "%s %v"}, "EUR 1 234.50"},
+		{"EUR", &Options{CurrencyPrecision: 3}, "1 234.500 €"},
+		{"EUR", &Options{CurrencyPrecision: NoMinorUnits}, "1 235 €"},
+		{"XXX", &Options{}, "€ 1 234.50"},
+		{"", &Options{}, "€ 1 234.50"},
+	} {
+		doc, _ := New(Invoice, test.options)
+		doc.SetCurrency(test.currency)
+		if got := doc.ac.FormatMoneyDecimal(amount); got != test.expected {
+			t.Fatalf("expected %q for %s, got %q", test.expected, test.currency, got)
+		}
+	}
+
+	// Setting the currency later rebuilds the format
+	doc, _ := New(Invoice, &Options{})
+	doc.SetCurrency("VND")
+	if got := doc.ac.FormatMoneyDecimal(amount); got != "1 235 VND" {
+		t.Fatalf("expected 1 235 VND, got %q", got)
+	}
+
+	// Options of a document decoded from JSON override the table as well
+	var decoded Document
+	if err := json.Unmarshal([]byte(`{"currency":"USD","options":{"currency_precision":-1,"currency_thousand":","}}`), &decoded); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	decoded.prepareAccounting()
+	if got := decoded.ac.FormatMoneyDecimal(amount); got != "$ 1,235" {
+		t.Fatalf("expected $ 1,235, got %q", got)
+	}
+
+	// Registered currencies are looked up while documents are built
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterCurrency(&Currency{Code: "xts", Symbol: "¤", MinorUnits: 1})
+		}()
+		go func() {
+			defer wg.Done()
+			LookupCurrency("XTS")
+		}()
+	}
+	wg.Wait()
+
+	registered, _ := New(Invoice, &Options{})
+	registered.SetCurrency("XTS")
+	if got := registered.ac.FormatMoneyDecimal(amount); got != "¤ 1 234.5" {
+		t.Fatalf("expected ¤ 1 234.5, got %q", got)
+	}
+
+	// Secondary currency of an exchange rate uses the table too
+	doc.SetExchangeRate(&ExchangeRate{Currency: "EUR", Rate: "0.00004"})
+	if err := doc.prepareExchangeRate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+	if got := doc.ExchangeRate._ac.FormatMoneyDecimal(doc.ExchangeRate.Convert(decimal.NewFromFloat(1000000))); got != "40.00 €" {
+		t.Fatalf("expected 40.00 €, got %q", got)
+	}
+}
+
 func TestNew(t *testing.T) {
 	doc, err := New(Invoice, &Options{
 		TextTypeInvoice:   "FACTURE",
@@ -48,8 +1053,8 @@ func TestNew(t *testing.T) {
 	doc.SetDescription("A description àç")
 	doc.SetNotes("I léove croissant cotton candy. Carrot cake sweet Ià love sweet roll cake powder! I love croissant cotton candy. Carrot cake sweet I love sweet roll cake powder! I love croissant cotton candy. Carrot cake sweet I love sweet roll cake powder! I love croissant cotton candy. Carrot cake sweet I love sweet roll cake powder! ")
 
-	doc.SetDate("02/03/2021")
-	doc.SetPaymentTerm("02/04/2021")
+	doc.SetDate(time.Date(2021, time.March, 2, 0, 0, 0, 0, time.UTC))
+	doc.SetPaymentTerm(time.Date(2021, time.April, 2, 0, 0, 0, 0, time.UTC))
 
 	logoBytes, _ := os.ReadFile("./example_logo.png")
 
@@ -138,7 +1143,7 @@ func TestNew(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 
-	err = pdf.OutputFileAndClose("out.pdf")
+	err = pdf.OutputFileAndClose(filepath.Join(t.TempDir(), "out.pdf"))
 
 	if err != nil {
 		t.Errorf(err.Error())
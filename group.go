@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// GroupTotal represent the items of a group and their totals, before document discount
+type GroupTotal struct {
+	Name            string          `json:"name,omitempty"`
+	Items           []*Item         `json:"-"`
+	TotalWithoutTax decimal.Decimal `json:"total_without_tax"`
+	Tax             decimal.Decimal `json:"tax"`
+	TotalWithTax    decimal.Decimal `json:"total_with_tax"`
+}
+
+// GroupTotals return the items grouped by Item.Group, in order of first appearance
+// Items without group are returned in a group without name
+func (doc *Document) GroupTotals() []*GroupTotal {
+	var groups []*GroupTotal
+	byName := make(map[string]*GroupTotal)
+
+	for _, item := range doc.Items {
+		group, ok := byName[item.Group]
+		if !ok {
+			group = &GroupTotal{
+				Name:            item.Group,
+				TotalWithoutTax: decimal.NewFromFloat(0),
+				Tax:             decimal.NewFromFloat(0),
+				TotalWithTax:    decimal.NewFromFloat(0),
+			}
+			byName[item.Group] = group
+			groups = append(groups, group)
+		}
+
+		group.Items = append(group.Items, item)
+		for _, priced := range item.pricedItems() {
+			group.TotalWithoutTax = group.TotalWithoutTax.Add(priced.TotalWithoutTaxAndWithDiscount())
+			group.Tax = group.Tax.Add(priced.TaxWithTotalDiscounted())
+			group.TotalWithTax = group.TotalWithTax.Add(priced.TotalWithTaxAndDiscount())
+		}
+	}
+
+	return groups
+}
+
+// lines return the items of the group followed by their bundle components, in rendering order
+func (g *GroupTotal) lines() []*Item {
+	var lines []*Item
+	for _, item := range g.Items {
+		lines = append(lines, item.withChildren()...)
+	}
+
+	return lines
+}
+
+// appendItemsGroupHeader draws the header row of a group of items
+func (doc *Document) appendItemsGroupHeader(group *GroupTotal) {
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.CellFormat(ItemsTableWidth, 6, doc.encodeString(group.Name), "B", 0, "L", false, 0, "")
+
+	// Reset font
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetY(doc.pdf.GetY() + 8)
+}
+
+// appendItemsGroupSubtotal draws the subtotal row of a group of items
+// The label goes in the name column and the amount in the total with tax column, the last one when missing
+func (doc *Document) appendItemsGroupSubtotal(group *GroupTotal) {
+	columns := doc.itemColumns()
+	if len(columns) == 0 {
+		return
+	}
+
+	amountColumn := columns[len(columns)-1]
+	labelColumn := columns[0]
+	for _, column := range columns {
+		switch column.Key {
+		case ItemColumnTotalWithTax:
+			amountColumn = column
+		case ItemColumnName:
+			labelColumn = column
+		}
+	}
+
+	baseY := doc.pdf.GetY()
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+	doc.pdf.SetDrawColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Line(BaseMargin, baseY, BaseMargin+ItemsTableWidth, baseY)
+
+	doc.pdf.SetXY(labelColumn.x, baseY)
+	doc.pdf.CellFormat(
+		labelColumn.width,
+		6,
+		doc.encodeString(fmt.Sprintf("%s %s", doc.Options.TextItemsGroupSubtotal, group.Name)),
+		"0",
+		0,
+		"L",
+		false,
+		0,
+		"",
+	)
+
+	doc.pdf.SetXY(amountColumn.x, baseY)
+	doc.pdf.CellFormat(
+		amountColumn.width,
+		6,
+		doc.encodeString(doc.ac.FormatMoneyDecimal(group.TotalWithTax)),
+		"0",
+		0,
+		amountColumn.align(),
+		false,
+		0,
+		"",
+	)
+
+	// Reset font
+	doc.pdf.SetDrawColor(0, 0, 0)
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetY(baseY + 6)
+}
@@ -0,0 +1,36 @@
+package generator
+
+import "errors"
+
+// ErrInvalidGTIN when an item GTIN has an invalid length or check digit
+var ErrInvalidGTIN = errors.New("invalid GTIN")
+
+// isValidGTIN return true if code is a GTIN-8, GTIN-12 (UPC), GTIN-13 (EAN) or GTIN-14 with a valid check digit
+func isValidGTIN(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	// Weights alternate 3 and 1 from the rightmost digit before the check digit
+	sum := 0
+	for index := len(code) - 2; index >= 0; index-- {
+		digit := int(code[index] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+
+		if (len(code)-2-index)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := int(code[len(code)-1] - '0')
+	if check < 0 || check > 9 {
+		return false
+	}
+
+	return (10-sum%10)%10 == check
+}
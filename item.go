@@ -1,43 +1,118 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
 
+// ErrInvalidBackordered when the backordered quantity exceeds the item quantity
+var ErrInvalidBackordered = errors.New("invalid backordered quantity")
+
+// ErrMissingUnitCost when an item of a priced document has no unit cost
+var ErrMissingUnitCost = errors.New("missing unit cost")
+
 // Item represent a 'product' or a 'service'
 type Item struct {
-	Name        string    `json:"name,omitempty" validate:"required"`
-	Description string    `json:"description,omitempty"`
-	UnitCost    string    `json:"unit_cost,omitempty"`
-	Quantity    string    `json:"quantity,omitempty"`
-	Tax         *Tax      `json:"tax,omitempty"`
-	Discount    *Discount `json:"discount,omitempty"`
-
-	_unitCost decimal.Decimal
-	_quantity decimal.Decimal
+	Name          string       `json:"name,omitempty" validate:"required"`
+	Group         string       `json:"group,omitempty"` // Section the item is listed under ex Design, Hosting
+	Description   string       `json:"description,omitempty"`
+	UnitCost      string       `json:"unit_cost,omitempty"`
+	Quantity      string       `json:"quantity,omitempty"`
+	SKU           string       `json:"sku,omitempty"`
+	GTIN          string       `json:"gtin,omitempty"`        // GTIN-8, GTIN-12 (UPC), GTIN-13 (EAN) or GTIN-14
+	HSCode        string       `json:"hs_code,omitempty"`     // Customs harmonized system code
+	Unit          string       `json:"unit,omitempty"`        // Unit of measure ex pcs, kg, h, m²
+	Backordered   string       `json:"backordered,omitempty"` // Quantity not delivered yet, used by delivery notes
+	Tax           *Tax         `json:"tax,omitempty"`
+	Taxes         []*Tax       `json:"taxes,omitempty"`         // Additional taxes, applied after Tax
+	TaxInclusive  bool         `json:"tax_inclusive,omitempty"` // UnitCost includes taxes
+	Discount      *Discount    `json:"discount,omitempty"`
+	Children      []*Item      `json:"children,omitempty"`       // Bundle components, rendered indented under the item
+	TimeEntries   []*TimeEntry `json:"time_entries,omitempty"`   // Timesheet, the line aggregates the entries hours and amounts
+	BundlePricing string       `json:"bundle_pricing,omitempty"` // parent or children, default parent
+	Pricing       *Pricing     `json:"pricing,omitempty"`        // Usage based pricing, tiers and included units
+
+	_unitCost    decimal.Decimal
+	_quantity    decimal.Decimal
+	_backordered decimal.Decimal
+	_rounding    *rounding
+	_depth       int  // Nesting level in a bundle
+	_included    bool // Component of a bundle priced by its parent
 }
 
 // Prepare convert strings to decimal
 func (i *Item) Prepare() error {
-	// Unit cost
-	unitCost, err := decimal.NewFromString(i.UnitCost)
-	if err != nil {
-		return err
+	// Unit cost, may be empty on delivery notes
+	if len(i.UnitCost) > 0 {
+		unitCost, err := decimal.NewFromString(i.UnitCost)
+		if err != nil {
+			return err
+		}
+		i._unitCost = unitCost
 	}
-	i._unitCost = unitCost
 
-	// Quantity
-	quantity, err := decimal.NewFromString(i.Quantity)
-	if err != nil {
-		return err
+	// Quantity, a bundle priced by its children and the components priced by their parent default to one
+	quantity := decimal.NewFromFloat(1)
+	if len(i.TimeEntries) > 0 {
+		if err := i.prepareTimeEntries(); err != nil {
+			return err
+		}
+		quantity = i._quantity
+	} else if len(i.Quantity) > 0 || !(i.rollsUp() || i._included) {
+		parsed, err := decimal.NewFromString(i.Quantity)
+		if err != nil {
+			return err
+		}
+		quantity = parsed
 	}
 	i._quantity = quantity
 
-	// Tax
-	if i.Tax != nil {
-		if err := i.Tax.Prepare(); err != nil {
+	// Bundle pricing
+	if len(i.BundlePricing) == 0 {
+		i.BundlePricing = BundlePricingParent
+	}
+	if i.BundlePricing != BundlePricingParent && i.BundlePricing != BundlePricingChildren {
+		return ErrInvalidBundlePricing
+	}
+
+	// A bundle priced by its children is the sum of their lines, without quantity nor discount of its own
+	if i.rollsUp() && (!quantity.Equal(decimal.NewFromFloat(1)) || i.Discount != nil) {
+		return ErrInvalidBundlePricing
+	}
+
+	// Usage based pricing, of a consumed quantity
+	if i.Pricing != nil {
+		if err := i.Pricing.Prepare(); err != nil {
+			return err
+		}
+		if quantity.IsNegative() {
+			return ErrInvalidPricing
+		}
+	}
+
+	// GTIN
+	if len(i.GTIN) > 0 && !isValidGTIN(i.GTIN) {
+		return ErrInvalidGTIN
+	}
+
+	// Backordered
+	if len(i.Backordered) > 0 {
+		backordered, err := decimal.NewFromString(i.Backordered)
+		if err != nil {
+			return err
+		}
+		if backordered.IsNegative() || backordered.GreaterThan(quantity) {
+			return ErrInvalidBackordered
+		}
+		i._backordered = backordered
+	}
+
+	// Taxes
+	for _, tax := range i.taxes() {
+		if err := tax.Prepare(); err != nil {
 			return err
 		}
 	}
@@ -54,16 +129,44 @@ func (i *Item) Prepare() error {
 
 // TotalWithoutTaxAndWithoutDiscount returns the total without tax and without discount
 func (i *Item) TotalWithoutTaxAndWithoutDiscount() decimal.Decimal {
-	quantity, _ := decimal.NewFromString(i.Quantity)
-	price, _ := decimal.NewFromString(i.UnitCost)
-	total := price.Mul(quantity)
+	if i.TaxInclusive {
+		return i.netFromGross(i.totalWithoutDiscount())
+	}
 
-	return total
+	return i.totalWithoutDiscount()
 }
 
 // TotalWithoutTaxAndWithDiscount returns the total without tax and with discount
 func (i *Item) TotalWithoutTaxAndWithDiscount() decimal.Decimal {
-	total := i.TotalWithoutTaxAndWithoutDiscount()
+	if i.TaxInclusive {
+		return i.netFromGross(i.totalWithDiscount())
+	}
+
+	return i.totalWithDiscount()
+}
+
+// totalWithoutDiscount returns unit cost * quantity
+// It includes taxes when the item is tax inclusive
+func (i *Item) totalWithoutDiscount() decimal.Decimal {
+	// Timesheet lines are the sum of their entries
+	if len(i.TimeEntries) > 0 {
+		return i._rounding.roundLine(i.timeEntriesAmount())
+	}
+
+	// Usage based lines are the sum of their tier charges
+	if i.Pricing != nil {
+		return i._rounding.roundLine(i.meteredAmount())
+	}
+
+	total := i._unitCost.Mul(i._quantity)
+
+	return i._rounding.roundLine(total)
+}
+
+// totalWithDiscount returns unit cost * quantity minus the item discount
+// It includes taxes when the item is tax inclusive
+func (i *Item) totalWithDiscount() decimal.Decimal {
+	total := i.totalWithoutDiscount()
 
 	// Check discount
 	if i.Discount != nil {
@@ -78,7 +181,28 @@ func (i *Item) TotalWithoutTaxAndWithDiscount() decimal.Decimal {
 		}
 	}
 
-	return total
+	return i._rounding.roundLine(total)
+}
+
+// netFromGross back-calculate the amount without tax from an amount including item taxes
+// Taxes are linear in their base, so tax(net) = tax(0) + net * (tax(1) - tax(0))
+func (i *Item) netFromGross(gross decimal.Decimal) decimal.Decimal {
+	fixedTaxes := decimal.NewFromFloat(0)
+	for _, applied := range applyTaxes(i.taxes(), decimal.NewFromFloat(0), decimal.NewFromFloat(1), nil) {
+		fixedTaxes = fixedTaxes.Add(applied.amount)
+	}
+
+	unitTaxes := decimal.NewFromFloat(0)
+	for _, applied := range applyTaxes(i.taxes(), decimal.NewFromFloat(1), decimal.NewFromFloat(1), nil) {
+		unitTaxes = unitTaxes.Add(applied.amount)
+	}
+
+	divider := decimal.NewFromFloat(1).Add(unitTaxes).Sub(fixedTaxes)
+	if divider.IsZero() {
+		return gross
+	}
+
+	return i._rounding.round(gross.Sub(fixedTaxes).Div(divider))
 }
 
 // TotalWithTaxAndDiscount returns the total with tax and discount
@@ -90,30 +214,106 @@ func (i *Item) TotalWithTaxAndDiscount() decimal.Decimal {
 func (i *Item) TaxWithTotalDiscounted() decimal.Decimal {
 	result := decimal.NewFromFloat(0)
 
-	if i.Tax == nil {
-		return result
+	for _, applied := range i.appliedTaxes(i.TotalWithoutTaxAndWithDiscount()) {
+		result = result.Add(applied.amount)
 	}
 
-	totalHT := i.TotalWithoutTaxAndWithDiscount()
-	taxType, taxAmount := i.Tax.getTax()
+	return result
+}
 
-	if taxType == TaxTypeAmount {
-		result = taxAmount
-	} else {
-		divider := decimal.NewFromFloat(100)
-		result = totalHT.Mul(taxAmount.Div(divider))
+// appliedTaxes compute the item taxes on base
+func (i *Item) appliedTaxes(base decimal.Decimal) []*appliedTax {
+	// Ratio of the line kept after the document discount
+	ratio := decimal.NewFromFloat(1)
+	if lineTotal := i.TotalWithoutTaxAndWithDiscount(); !lineTotal.IsZero() {
+		ratio = base.Div(lineTotal)
 	}
 
-	return result
+	applied := applyTaxes(i.taxes(), base, ratio, i._rounding)
+
+	// Taxes of a tax inclusive line must add up to the price the customer saw,
+	// the rounding difference goes to the last tax
+	if i.TaxInclusive && len(applied) > 0 && base.Equal(i.TotalWithoutTaxAndWithDiscount()) {
+		residual := i.totalWithDiscount().Sub(base)
+		for _, tax := range applied {
+			residual = residual.Sub(tax.amount)
+		}
+
+		last := applied[len(applied)-1]
+		last.amount = last.amount.Add(residual)
+	}
+
+	return applied
+}
+
+// taxes return all the taxes applied to the item, in order
+func (i *Item) taxes() []*Tax {
+	taxes := make([]*Tax, 0, len(i.Taxes)+1)
+
+	if i.Tax != nil {
+		taxes = append(taxes, i.Tax)
+	}
+
+	return append(taxes, i.Taxes...)
 }
 
 // appendColTo document doc
 func (i *Item) appendColTo(options *Options, doc *Document) {
 	// Get base Y (top of line)
-	baseY := doc.pdf.GetY() + 5
+	baseY := doc.pdf.GetY()
+	columns := doc.itemColumns()
+
+	// Name column drives the line height
+	colHeight := float64(4)
+	for _, column := range columns {
+		if column.Key == ItemColumnName {
+			i.appendNameColTo(doc, column)
+
+			// Compute line height
+			colHeight = doc.pdf.GetY() - baseY
+		}
+	}
+
+	for _, column := range columns {
+		doc.pdf.SetXY(column.x, baseY)
+
+		switch {
+		case column.Key == ItemColumnName:
+			continue
+		case column.Key == ItemColumnDiscount && column.Formatter == nil:
+			discountTitle, discountDesc := i.discountColumn(doc)
+			i.appendTwoLinesCell(doc, column, baseY, colHeight, discountTitle, discountDesc)
+		case column.Key == ItemColumnTax && column.Formatter == nil:
+			taxTitle, taxDesc := i.taxColumn(doc)
+			i.appendTwoLinesCell(doc, column, baseY, colHeight, taxTitle, taxDesc)
+		default:
+			doc.pdf.CellFormat(
+				column.width,
+				colHeight,
+				doc.encodeString(column.cellValue(doc, i)),
+				"0",
+				0,
+				column.align(),
+				false,
+				0,
+				"",
+			)
+		}
+	}
+
+	// Set Y for next line
+	doc.pdf.SetY(baseY + colHeight)
+}
+
+// appendNameColTo document doc
+func (i *Item) appendNameColTo(doc *Document, column *itemColumnLayout) {
+	// Bundle components are indented under their parent
+	indent := float64(i._depth) * BundleIndent
+	x := column.x + indent
+	width := column.width - indent
 
 	// Name - use MultiCell but with proper line height to prevent silver text effect
-	doc.pdf.SetX(ItemColNameOffset)
+	doc.pdf.SetXY(x, doc.pdf.GetY())
 	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
 	doc.pdf.SetTextColor(
 		doc.Options.BaseTextColor[0],
@@ -121,142 +321,48 @@ func (i *Item) appendColTo(options *Options, doc *Document) {
 		doc.Options.BaseTextColor[2],
 	)
 	doc.pdf.MultiCell(
-		ItemColUnitPriceOffset-ItemColNameOffset,
+		width,
 		4,
-		doc.encodeString(i.Name),
-		"",
-		"",
-		false,
-	)
-
-	// Description - removed to eliminate silver text lines
-	// if len(i.Description) > 0 {
-	// 	doc.pdf.SetX(ItemColNameOffset)
-	// 	doc.pdf.SetY(doc.pdf.GetY() + 1)
-
-	// 	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
-	// 	doc.pdf.SetTextColor(
-	// 		doc.Options.GreyTextColor[0],
-	// 		doc.Options.GreyTextColor[1],
-	// 		doc.Options.GreyTextColor[2],
-	// 	)
-
-	// 	doc.pdf.MultiCell(
-	// 		ItemColUnitPriceOffset-ItemColNameOffset,
-	// 		3,
-	// 		doc.encodeString(i.Description),
-	// 		"",
-	// 		"",
-	// 		false,
-	// 	)
-
-	// 	// Reset font
-	// 	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
-	// 	doc.pdf.SetTextColor(
-	// 		doc.Options.BaseTextColor[0],
-	// 		doc.Options.BaseTextColor[1],
-	// 		doc.Options.BaseTextColor[2],
-	// 	)
-	// }
-
-	// Compute line height
-	colHeight := doc.pdf.GetY() - baseY
-
-	// Unit price
-	doc.pdf.SetY(baseY)
-	doc.pdf.SetX(ItemColUnitPriceOffset)
-	doc.pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		colHeight,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(i._unitCost)),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Quantity
-	doc.pdf.SetX(ItemColQuantityOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		colHeight,
-		doc.encodeString(i._quantity.String()),
-		"0",
-		0,
+		doc.encodeString(column.cellValue(doc, i)),
 		"",
+		column.align(),
 		false,
-		0,
-		"",
 	)
 
-	// Total HT
-	doc.pdf.SetX(ItemColTotalHTOffset)
-	doc.pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		colHeight,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(i.TotalWithoutTaxAndWithoutDiscount())),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	// Tax exemption
+	for _, tax := range i.taxes() {
+		if !tax.isExempted() {
+			continue
+		}
 
-	// Discount
-	doc.pdf.SetX(ItemColDiscountOffset)
-	if i.Discount == nil {
-		doc.pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight,
-			doc.encodeString("0 VND"),
-			"0",
-			0,
+		doc.pdf.SetX(x)
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.SetTextColor(
+			doc.Options.GreyTextColor[0],
+			doc.Options.GreyTextColor[1],
+			doc.Options.GreyTextColor[2],
+		)
+		doc.pdf.MultiCell(
+			width,
+			3,
+			doc.encodeString(taxCategoryLabel(tax.Category, doc.Options)),
 			"",
-			false,
-			0,
 			"",
+			false,
 		)
-	} else {
-		// If discount
-		discountType, discountAmount := i.Discount.getDiscount()
-		var discountTitle string
-		var discountDesc string
-
-		dCost := i.TotalWithoutTaxAndWithoutDiscount()
-		if discountType == DiscountTypePercent {
-			discountTitle = fmt.Sprintf("%s %s", discountAmount, doc.encodeString("%"))
-
-			// get amount from percent
-			dAmount := dCost.Mul(discountAmount.Div(decimal.NewFromFloat(100)))
-			discountDesc = fmt.Sprintf("-%s", doc.ac.FormatMoneyDecimal(dAmount))
-		} else {
-			discountTitle = fmt.Sprintf("%s %s", discountAmount, doc.encodeString("€"))
 
-			// get percent from amount
-			dPerc := discountAmount.Mul(decimal.NewFromFloat(100))
-			dPerc = dPerc.Div(dCost)
-			discountDesc = fmt.Sprintf("-%s %%", dPerc.StringFixed(2))
-		}
-
-		// discount title
-		// lastY := doc.pdf.GetY()
-		doc.pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight/2,
-			doc.encodeString(discountTitle),
-			"0",
-			0,
-			"LB",
-			false,
-			0,
-			"",
+		// Reset font
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
 		)
+	}
 
-		// discount desc
-		doc.pdf.SetXY(ItemColDiscountOffset, baseY+(colHeight/2))
+	// Description
+	if len(i.Description) > 0 {
+		doc.pdf.SetXY(x, doc.pdf.GetY()+1)
 		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
 		doc.pdf.SetTextColor(
 			doc.Options.GreyTextColor[0],
@@ -264,123 +370,120 @@ func (i *Item) appendColTo(options *Options, doc *Document) {
 			doc.Options.GreyTextColor[2],
 		)
 
-		doc.pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight/2,
-			doc.encodeString(discountDesc),
-			"0",
-			0,
-			"LT",
-			false,
-			0,
+		doc.pdf.MultiCell(
+			width,
+			3,
+			doc.encodeString(i.Description),
 			"",
+			column.align(),
+			false,
+		)
+
+		// Reset font
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+		doc.pdf.SetTextColor(
+			doc.Options.BaseTextColor[0],
+			doc.Options.BaseTextColor[1],
+			doc.Options.BaseTextColor[2],
 		)
+	}
+
+	// Tier breakdown
+	if lines := i.tierBreakdownLines(doc); len(lines) > 0 {
+		doc.pdf.SetXY(x, doc.pdf.GetY()+1)
+		doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+		doc.pdf.SetTextColor(
+			doc.Options.GreyTextColor[0],
+			doc.Options.GreyTextColor[1],
+			doc.Options.GreyTextColor[2],
+		)
+
+		for _, line := range lines {
+			doc.pdf.SetX(x)
+			doc.pdf.MultiCell(width, 3, doc.encodeString(line), "", column.align(), false)
+		}
 
-		// reset font and y
+		// Reset font
 		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
 		doc.pdf.SetTextColor(
 			doc.Options.BaseTextColor[0],
 			doc.Options.BaseTextColor[1],
 			doc.Options.BaseTextColor[2],
 		)
-		doc.pdf.SetY(baseY)
 	}
+}
 
-	// // Tax
-	// doc.pdf.SetX(ItemColTaxOffset)
-	// if i.Tax == nil {
-	// 	// If no tax
-	// 	doc.pdf.CellFormat(
-	// 		ItemColDiscountOffset-ItemColTaxOffset,
-	// 		colHeight,
-	// 		doc.encodeString("--"),
-	// 		"0",
-	// 		0,
-	// 		"",
-	// 		false,
-	// 		0,
-	// 		"",
-	// 	)
-	// } else {
-	// 	// If tax
-	// 	taxType, taxAmount := i.Tax.getTax()
-	// 	var taxTitle string
-	// 	var taxDesc string
-
-	// 	if taxType == TaxTypePercent {
-	// 		taxTitle = fmt.Sprintf("%s %s", taxAmount, "%")
-	// 		// get amount from percent
-	// 		dCost := i.TotalWithoutTaxAndWithDiscount()
-	// 		dAmount := dCost.Mul(taxAmount.Div(decimal.NewFromFloat(100)))
-	// 		taxDesc = doc.ac.FormatMoneyDecimal(dAmount)
-	// 	} else {
-	// 		taxTitle = fmt.Sprintf("%s %s", doc.ac.Symbol, taxAmount)
-	// 		dCost := i.TotalWithoutTaxAndWithDiscount()
-	// 		dPerc := taxAmount.Mul(decimal.NewFromFloat(100))
-	// 		dPerc = dPerc.Div(dCost)
-	// 		// get percent from amount
-	// 		taxDesc = fmt.Sprintf("%s %%", dPerc.StringFixed(2))
-	// 	}
-
-	// 	// tax title
-	// 	// lastY := doc.pdf.GetY()
-	// 	doc.pdf.CellFormat(
-	// 		ItemColDiscountOffset-ItemColTaxOffset,
-	// 		colHeight/2,
-	// 		doc.encodeString(taxTitle),
-	// 		"0",
-	// 		0,
-	// 		"LB",
-	// 		false,
-	// 		0,
-	// 		"",
-	// 	)
-
-	// 	// tax desc
-	// 	doc.pdf.SetXY(ItemColTaxOffset, baseY+(colHeight/2))
-	// 	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
-	// 	doc.pdf.SetTextColor(
-	// 		doc.Options.GreyTextColor[0],
-	// 		doc.Options.GreyTextColor[1],
-	// 		doc.Options.GreyTextColor[2],
-	// 	)
-
-	// 	doc.pdf.CellFormat(
-	// 		ItemColDiscountOffset-ItemColTaxOffset,
-	// 		colHeight/2,
-	// 		doc.encodeString(taxDesc),
-	// 		"0",
-	// 		0,
-	// 		"LT",
-	// 		false,
-	// 		0,
-	// 		"",
-	// 	)
-
-	// 	// reset font and y
-	// 	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
-	// 	doc.pdf.SetTextColor(
-	// 		doc.Options.BaseTextColor[0],
-	// 		doc.Options.BaseTextColor[1],
-	// 		doc.Options.BaseTextColor[2],
-	// 	)
-	// 	doc.pdf.SetY(baseY)
-	// }
-
-	// TOTAL TTC
-	doc.pdf.SetX(ItemColTotalTTCOffset)
+// appendTwoLinesCell draws title on the top half of the cell and desc in grey on the bottom half
+func (i *Item) appendTwoLinesCell(doc *Document, column *itemColumnLayout, baseY float64, colHeight float64, title string, desc string) {
+	doc.pdf.SetXY(column.x, baseY)
 	doc.pdf.CellFormat(
-		190-ItemColTotalTTCOffset,
-		colHeight,
-		doc.encodeString(doc.ac.FormatMoneyDecimal(i.TotalWithTaxAndDiscount())),
+		column.width,
+		colHeight/2,
+		doc.encodeString(title),
 		"0",
 		0,
+		column.align()+"B",
+		false,
+		0,
 		"",
+	)
+
+	// desc
+	doc.pdf.SetXY(column.x, baseY+(colHeight/2))
+	doc.pdf.SetFont(doc.Options.Font, "", SmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+
+	doc.pdf.CellFormat(
+		column.width,
+		colHeight/2,
+		doc.encodeString(desc),
+		"0",
+		0,
+		column.align()+"T",
 		false,
 		0,
 		"",
 	)
 
-	// Set Y for next line
-	doc.pdf.SetY(baseY + colHeight)
+	// reset font and y
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.SetY(baseY)
+}
+
+// taxColumn return the rates and the tax amount displayed in the item tax column
+func (i *Item) taxColumn(doc *Document) (string, string) {
+	if i.rollsUp() {
+		return "", ""
+	}
+
+	taxes := i.taxes()
+	if len(taxes) == 0 || i._included {
+		return "--", ""
+	}
+
+	rates := make([]string, 0, len(taxes))
+	for _, tax := range taxes {
+		if label := taxCategoryLabel(tax.Category, doc.Options); len(label) > 0 {
+			rates = append(rates, label)
+			continue
+		}
+
+		taxType, taxAmount := tax.getTax()
+		if taxType == TaxTypePercent {
+			rates = append(rates, fmt.Sprintf("%s %%", taxAmount.String()))
+		} else {
+			rates = append(rates, doc.ac.FormatMoneyDecimal(taxAmount))
+		}
+	}
+
+	return strings.Join(rates, " + "), doc.ac.FormatMoneyDecimal(i.TaxWithTotalDiscounted())
 }
@@ -0,0 +1,268 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidItemColumn when an item column has no key or a negative size
+var ErrInvalidItemColumn = errors.New("invalid item column")
+
+// Item column keys
+const (
+	ItemColumnName            string = "name"
+	ItemColumnUnitPrice       string = "unit_price"
+	ItemColumnQuantity        string = "quantity"
+	ItemColumnTotalWithoutTax string = "total_without_tax"
+	ItemColumnDiscount        string = "discount"
+	ItemColumnTax             string = "tax"
+	ItemColumnTotalWithTax    string = "total_with_tax"
+	ItemColumnSKU             string = "sku"
+	ItemColumnGTIN            string = "gtin"
+	ItemColumnHSCode          string = "hs_code"
+	ItemColumnUnit            string = "unit"
+)
+
+// ItemsTableWidth is the width of the items table
+const ItemsTableWidth float64 = 190
+
+// ItemColumn define a column of the items table
+// Width is in mm, columns without Width share the remaining width according to their Weight
+type ItemColumn struct {
+	Key       string                                 `json:"key"`             // One of ItemColumn* keys or a custom key
+	Title     string                                 `json:"title,omitempty"` // Default title of the key when empty
+	Width     float64                                `json:"width,omitempty"`
+	Weight    float64                                `json:"weight,omitempty"` // 1 when Width and Weight are empty
+	Align     string                                 `json:"align,omitempty"`  // L, C or R, default L
+	Formatter func(doc *Document, item *Item) string `json:"-"`                // Replace the cell content
+}
+
+// itemColumnLayout is an item column placed in the table
+type itemColumnLayout struct {
+	*ItemColumn
+	x     float64
+	width float64
+}
+
+// defaultItemColumns return the item columns used when Options.ItemColumns is empty
+func defaultItemColumns(options *Options) []*ItemColumn {
+	columns := []*ItemColumn{
+		{Key: ItemColumnName, Width: ItemColUnitPriceOffset - ItemColNameOffset},
+		{Key: ItemColumnUnitPrice, Width: ItemColQuantityOffset - ItemColUnitPriceOffset},
+		{Key: ItemColumnQuantity, Width: ItemColTotalHTOffset - ItemColQuantityOffset},
+		{Key: ItemColumnTotalWithoutTax, Width: ItemColDiscountOffset - ItemColTotalHTOffset},
+	}
+
+	// Discount is narrower when the tax column is shown
+	if options.ShowItemsTax {
+		columns = append(columns,
+			&ItemColumn{Key: ItemColumnDiscount, Width: ItemColTaxOffset - ItemColDiscountOffset},
+			&ItemColumn{Key: ItemColumnTax, Width: ItemColTotalTTCOffset - ItemColTaxOffset},
+		)
+	} else {
+		columns = append(columns, &ItemColumn{Key: ItemColumnDiscount, Width: ItemColTotalTTCOffset - ItemColDiscountOffset})
+	}
+
+	return append(columns, &ItemColumn{Key: ItemColumnTotalWithTax, Weight: 1})
+}
+
+// validateItemColumns check the columns of Options.ItemColumns
+func validateItemColumns(columns []*ItemColumn) error {
+	for _, column := range columns {
+		if column == nil || len(column.Key) == 0 || column.Width < 0 || column.Weight < 0 {
+			return ErrInvalidItemColumn
+		}
+	}
+
+	return nil
+}
+
+// itemColumns return the items table columns placed from the table left margin
+func (doc *Document) itemColumns() []*itemColumnLayout {
+	columns := doc.Options.ItemColumns
+	if len(columns) == 0 {
+		columns = defaultItemColumns(doc.Options)
+	}
+
+	// Split the width left by fixed columns between weighted columns
+	remaining := ItemsTableWidth
+	weights := 0.0
+	for _, column := range columns {
+		if column.Width > 0 {
+			remaining -= column.Width
+		} else {
+			weights += column.weight()
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	layout := make([]*itemColumnLayout, 0, len(columns))
+	x := BaseMargin
+	for _, column := range columns {
+		width := column.Width
+		if width == 0 && weights > 0 {
+			width = remaining * column.weight() / weights
+		}
+
+		layout = append(layout, &itemColumnLayout{ItemColumn: column, x: x, width: width})
+		x += width
+	}
+
+	return layout
+}
+
+// weight return the column weight, 1 by default
+func (c *ItemColumn) weight() float64 {
+	if c.Weight == 0 {
+		return 1
+	}
+
+	return c.Weight
+}
+
+// align return the column alignment, left by default
+func (c *ItemColumn) align() string {
+	if len(c.Align) == 0 {
+		return "L"
+	}
+
+	return c.Align
+}
+
+// title return the column title, the default title of its key when empty
+func (c *ItemColumn) title(options *Options) string {
+	if len(c.Title) > 0 {
+		return c.Title
+	}
+
+	switch c.Key {
+	case ItemColumnName:
+		return options.TextItemsNameTitle
+	case ItemColumnUnitPrice:
+		return options.TextItemsUnitCostTitle
+	case ItemColumnQuantity:
+		return options.TextItemsQuantityTitle
+	case ItemColumnTotalWithoutTax:
+		return options.TextItemsTotalHTTitle
+	case ItemColumnDiscount:
+		return options.TextItemsDiscountTitle
+	case ItemColumnTax:
+		return options.TextItemsTaxTitle
+	case ItemColumnTotalWithTax:
+		return options.TextItemsTotalTTCTitle
+	case ItemColumnSKU:
+		return options.TextItemsSKUTitle
+	case ItemColumnGTIN:
+		return options.TextItemsGTINTitle
+	case ItemColumnHSCode:
+		return options.TextItemsHSCodeTitle
+	case ItemColumnUnit:
+		return options.TextItemsUnitTitle
+	}
+
+	return ""
+}
+
+// cellValue return the single line content of a column for item
+func (c *ItemColumn) cellValue(doc *Document, item *Item) string {
+	if c.Formatter != nil {
+		return c.Formatter(doc, item)
+	}
+
+	// Bundle components priced by their parent are included at zero
+	if item._included {
+		switch c.Key {
+		case ItemColumnUnitPrice, ItemColumnTotalWithoutTax, ItemColumnTotalWithTax:
+			return doc.ac.FormatMoneyDecimal(decimal.NewFromFloat(0))
+		}
+	}
+
+	// Bundles priced by their children show the sum of their children
+	if item.rollsUp() {
+		totalWithoutTax, totalWithTax := item.rolledUpTotals()
+		switch c.Key {
+		case ItemColumnUnitPrice:
+			return ""
+		case ItemColumnTotalWithoutTax:
+			return doc.ac.FormatMoneyDecimal(totalWithoutTax)
+		case ItemColumnTotalWithTax:
+			return doc.ac.FormatMoneyDecimal(totalWithTax)
+		}
+	}
+
+	// Tiered lines have no single unit price, see the tier breakdown
+	if item.isTiered() && c.Key == ItemColumnUnitPrice {
+		return ""
+	}
+
+	switch c.Key {
+	case ItemColumnName:
+		return item.Name
+	case ItemColumnUnitPrice:
+		return doc.ac.FormatMoneyDecimal(item._unitCost)
+	case ItemColumnQuantity:
+		return item.quantityWithUnit()
+	case ItemColumnTotalWithoutTax:
+		return doc.ac.FormatMoneyDecimal(item.TotalWithoutTaxAndWithoutDiscount())
+	case ItemColumnTotalWithTax:
+		return doc.ac.FormatMoneyDecimal(item.TotalWithTaxAndDiscount())
+	case ItemColumnSKU:
+		return item.SKU
+	case ItemColumnGTIN:
+		return item.GTIN
+	case ItemColumnHSCode:
+		return item.HSCode
+	case ItemColumnUnit:
+		return item.Unit
+	}
+
+	return ""
+}
+
+// discountColumn return the discount and its equivalent displayed in the item discount column
+func (i *Item) discountColumn(doc *Document) (string, string) {
+	if i.rollsUp() {
+		return "", ""
+	}
+
+	if i.Discount == nil || i._included {
+		return doc.ac.FormatMoneyDecimal(decimal.NewFromFloat(0)), ""
+	}
+
+	discountType, discountAmount := i.Discount.getDiscount()
+	dCost := i.TotalWithoutTaxAndWithoutDiscount()
+
+	if discountType == DiscountTypePercent {
+		// get amount from percent
+		dAmount := dCost.Mul(discountAmount.Div(decimal.NewFromFloat(100)))
+		return fmt.Sprintf("%s %%", discountAmount), fmt.Sprintf("-%s", doc.ac.FormatMoneyDecimal(dAmount))
+	}
+
+	// get percent from amount
+	if dCost.IsZero() {
+		return doc.ac.FormatMoneyDecimal(discountAmount), ""
+	}
+
+	dPerc := discountAmount.Mul(decimal.NewFromFloat(100)).Div(dCost)
+	return doc.ac.FormatMoneyDecimal(discountAmount), fmt.Sprintf("-%s %%", dPerc.StringFixed(2))
+}
+
+// quantityWithUnit return the item quantity followed by its unit of measure, ex 3 h
+func (i *Item) quantityWithUnit() string {
+	quantity := i._quantity.String()
+
+	// Timesheet hours as in the annex, ex 0.67 for two entries of 0:20
+	if len(i.TimeEntries) > 0 {
+		quantity = i._quantity.StringFixed(2)
+	}
+
+	if len(i.Unit) == 0 {
+		return quantity
+	}
+
+	return fmt.Sprintf("%s %s", quantity, i.Unit)
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// textOperator match a text drawn by fpdf in an uncompressed content stream
+var textOperator = regexp.MustCompile(`BT ([0-9.]+) ([0-9.]+) Td \((.*)\)Tj ET`)
+
+// textOrigin is the position, in pt from the page bottom left, of a text drawn by fpdf
+type textOrigin struct {
+	x float64
+	y float64
+}
+
+// textOrigins return the origins of the texts containing each needle
+func textOrigins(t *testing.T, doc *Document, needles ...string) map[string][]textOrigin {
+	doc.pdf.SetCompression(false)
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := pdf.Output(&output); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	origins := make(map[string][]textOrigin)
+	for _, match := range textOperator.FindAllStringSubmatch(output.String(), -1) {
+		for _, needle := range needles {
+			if strings.Contains(match[3], needle) {
+				x, _ := strconv.ParseFloat(match[1], 64)
+				y, _ := strconv.ParseFloat(match[2], 64)
+				origins[needle] = append(origins[needle], textOrigin{x: x, y: y})
+			}
+		}
+	}
+
+	return origins
+}
+
+// textBaselines return the baselines, in pt from the page bottom, of the texts containing each needle
+func textBaselines(t *testing.T, doc *Document, needles ...string) map[string][]float64 {
+	baselines := make(map[string][]float64)
+	for needle, origins := range textOrigins(t, doc, needles...) {
+		for _, origin := range origins {
+			baselines[needle] = append(baselines[needle], origin.y)
+		}
+	}
+
+	return baselines
+}
+
+func TestItemDescriptionDoesNotOverlap(t *testing.T) {
+	doc := newTestDocument(t, Invoice, &Options{})
+
+	description := strings.Repeat("Workflow audit ", 30)
+	doc.AppendItem(&Item{Name: "First service", Description: description, UnitCost: "12345", Quantity: "7"})
+	doc.AppendItem(&Item{Name: "Second service", UnitCost: "10", Quantity: "1"})
+
+	baselines := textBaselines(t, doc, "First service", "Workflow audit", "12 345", "Second service")
+
+	name := baselines["First service"]
+	lines := baselines["Workflow audit"]
+	price := baselines["12 345"]
+	next := baselines["Second service"]
+
+	if len(name) != 1 || len(price) != 1 || len(next) != 1 {
+		t.Fatalf("expected each text once, got %v", baselines)
+	}
+
+	// Description is rendered, on several lines
+	if len(lines) < 2 {
+		t.Fatalf("expected a multi line description, got %d lines", len(lines))
+	}
+	lastLine := lines[len(lines)-1]
+
+	// Price is vertically centered on the name and description block
+	center := (name[0] + lastLine) / 2
+	if math.Abs(price[0]-center) > 5 {
+		t.Fatalf("price baseline %.2f is not centered on the row (%.2f)", price[0], center)
+	}
+
+	// Next row starts below the description
+	if next[0] >= lastLine-SmallTextFontSize {
+		t.Fatalf("next row baseline %.2f overlaps the description (%.2f)", next[0], lastLine)
+	}
+}
+
+func TestDeliveryNoteListsBundleComponents(t *testing.T) {
+	doc := newTestDocument(t, DeliveryNote, &Options{})
+	doc.AppendItem(&Item{Name: "Starter kit", Quantity: "2", Children: []*Item{
+		{Name: "Router", Quantity: "2", Children: []*Item{
+			{Name: "Antenna", Quantity: "4"},
+		}},
+		{Name: "Cable", Quantity: "6", Backordered: "1"},
+	}})
+	doc.AppendItem(&Item{Name: "Manual", Quantity: "1"})
+
+	origins := textOrigins(t, doc, "Starter kit", "Router", "Antenna", "Cable", "Manual", "5 / 1")
+
+	names := []string{"Starter kit", "Router", "Antenna", "Cable", "Manual"}
+	for _, name := range names {
+		if len(origins[name]) != 1 {
+			t.Fatalf("expected %s once, got %v", name, origins[name])
+		}
+	}
+
+	// Components are listed under their parent, in order
+	for index := 1; index < len(names); index++ {
+		if origins[names[index]][0].y >= origins[names[index-1]][0].y {
+			t.Fatalf("expected %s below %s", names[index], names[index-1])
+		}
+	}
+
+	// And indented by their depth
+	parent := origins["Starter kit"][0].x
+	for name, depth := range map[string]float64{"Router": 1, "Antenna": 2, "Cable": 1, "Manual": 0} {
+		if expected := parent + depth*BundleIndent*72/25.4; math.Abs(origins[name][0].x-expected) > 0.01 {
+			t.Errorf("expected %s at x %.2f, got %.2f", name, expected, origins[name][0].x)
+		}
+	}
+
+	// Component delivery status
+	if len(origins["5 / 1"]) != 1 {
+		t.Fatalf("expected the cable delivery status, got %v", origins["5 / 1"])
+	}
+}
@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"image/jpeg"
-	"time"
 
 	"github.com/boombuler/barcode"
 	"github.com/boombuler/barcode/code128"
+	"github.com/creasty/defaults"
 	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
 )
 
 // MultiDocument represents a collection of documents to be generated in a single PDF
@@ -21,7 +22,10 @@ type MultiDocument struct {
 }
 
 // NewMultiDocument creates a new multi-document generator
+// Options are shared by all the documents and get the same defaults as New
 func NewMultiDocument(options *Options) *MultiDocument {
+	_ = defaults.Set(options)
+
 	pdf := fpdf.New("P", "mm", "A4", "")
 
 	return &MultiDocument{
@@ -116,11 +120,15 @@ func (md *MultiDocument) buildDocument(doc *Document) error {
 	// Append document metas (ref & version)
 	md.appendMetas(doc)
 
+	// Append expired watermark
+	md.appendExpiredWatermark(doc)
+	md.appendPaidStamp(doc)
+
 	// Append company contact to doc
 	companyBottom := doc.Company.appendCompanyContactToDoc(doc)
 
-	// Append customer contact to doc
-	customerBottom := doc.Customer.appendCustomerContactToDoc(doc)
+	// Append customer (or supplier) contact to doc
+	customerBottom := doc.recipient().appendCustomerContactToDoc(doc)
 
 	// Set position to the bottom of the higher contact section
 	if customerBottom > companyBottom {
@@ -132,29 +140,76 @@ func (md *MultiDocument) buildDocument(doc *Document) error {
 	// Append description
 	md.appendDescription(doc)
 
-	// Append items
-	md.appendItems(doc)
+	// Delivery notes only list goods, without prices nor totals
+	if doc.Type == DeliveryNote {
+		// Append items
+		doc.appendDeliveryItems()
 
-	// Check page height and add new page if needed
-	offset := md.pdf.GetY() + 30
-	if doc.Discount != nil {
-		offset += 15
-	}
-	if offset > MaxPageHeight {
-		md.pdf.AddPage()
-	}
+		// Check page height and add new page if needed
+		if md.pdf.GetY()+48 > MaxPageHeight {
+			md.pdf.AddPage()
+		}
+
+		// Append barcode parallel to signature
+		md.appendBarcode(doc)
+
+		// Append notes, signature is drawn on their right
+		signatureY := md.pdf.GetY()
+		md.appendNotes(doc)
+		md.pdf.SetY(signatureY)
+
+		// Append signature
+		doc.appendSignature()
+	} else {
+		// Append items
+		md.appendItems(doc)
+
+		// Check page height and add new page if needed
+		offset := md.pdf.GetY() + 30
+		if doc.Discount != nil {
+			offset += 15
+		}
+		offset += float64(10 * (len(doc.Adjustments) + len(doc.Charges)))
+		if len(doc.Payments) > 0 {
+			offset += 20
+		}
+		if doc.ExchangeRate != nil {
+			offset += 25
+		}
+		if offset > MaxPageHeight {
+			md.pdf.AddPage()
+		}
+
+		// Append barcode parallel to total
+		md.appendBarcode(doc)
+
+		// Append notes
+		md.appendNotes(doc)
+
+		// Append total
+		md.appendTotal(doc)
+
+		// Append tax summary
+		doc.appendTaxSummary()
 
-	// Append barcode parallel to total
-	md.appendBarcode(doc)
+		// Append payments history
+		doc.appendPaymentsHistory()
 
-	// Append notes
-	md.appendNotes(doc)
+		// Append tax exemption mentions
+		doc.appendTaxMentions()
 
-	// Append total
-	md.appendTotal(doc)
+		// Append payment term
+		md.appendPaymentTerm(doc)
 
-	// Append payment term
-	md.appendPaymentTerm(doc)
+		// Append payment terms details
+		doc.appendPaymentTerms()
+
+		// Append timesheet annex
+		doc.appendTimesheet()
+	}
+
+	// Append pro forma or credit note notice
+	md.appendNotice(doc)
 
 	return nil
 }
@@ -185,14 +240,77 @@ func (md *MultiDocument) appendMetas(doc *Document) {
 	md.pdf.CellFormat(80, 4, doc.encodeString(refString), "0", 0, "R", false, 0, "")
 
 	// Append date
-	date := time.Now().Format("02/01/2006")
-	if len(doc.Date) > 0 {
-		date = doc.Date
-	}
-	dateString := fmt.Sprintf("%s: %s", md.Options.TextDateTitle, date)
+	dateString := fmt.Sprintf("%s: %s", md.Options.TextDateTitle, doc.formatDate(doc.date()))
 	md.pdf.SetXY(120, BaseMarginTop+15)
 	md.pdf.SetFont(md.Options.Font, "", 10)
 	md.pdf.CellFormat(80, 4, doc.encodeString(dateString), "0", 0, "R", false, 0, "")
+
+	// Append additional metas (validity, credited invoice, payment...)
+	metaY := BaseMarginTop + 19
+	for _, meta := range doc.additionalMetas() {
+		md.pdf.SetXY(120, metaY)
+		md.pdf.SetFont(md.Options.Font, "", 10)
+		md.pdf.CellFormat(80, 4, doc.encodeString(meta), "0", 0, "R", false, 0, "")
+		metaY += 4
+	}
+}
+
+// appendExpiredWatermark to document when its validity date is over
+func (md *MultiDocument) appendExpiredWatermark(doc *Document) {
+	if !md.Options.ExpiredWatermark || !doc.IsExpired() {
+		return
+	}
+
+	currentX, currentY := md.pdf.GetXY()
+
+	md.pdf.SetFont(md.Options.BoldFont, "B", 80)
+	watermarkColor := md.getSafeColor(md.Options.ExpiredWatermarkColor, []int{200, 30, 30})
+	md.pdf.SetTextColor(watermarkColor[0], watermarkColor[1], watermarkColor[2])
+	md.pdf.SetAlpha(0.3, "Normal")
+
+	// Draw rotated text in the middle of the page
+	md.pdf.TransformBegin()
+	md.pdf.TransformRotate(45, 105, 148)
+	md.pdf.SetXY(5, 138)
+	md.pdf.CellFormat(200, 20, doc.encodeString(md.Options.TextExpiredWatermark), "0", 0, "C", false, 0, "")
+	md.pdf.TransformEnd()
+
+	// Reset alpha, font and position
+	md.pdf.SetAlpha(1, "Normal")
+	md.pdf.SetFont(md.Options.Font, "", 15)
+	baseTextColor := md.getSafeColor(md.Options.BaseTextColor, []int{35, 35, 35})
+	md.pdf.SetTextColor(baseTextColor[0], baseTextColor[1], baseTextColor[2])
+	md.pdf.SetXY(currentX, currentY)
+}
+
+// appendPaidStamp to document when its balance is settled
+func (md *MultiDocument) appendPaidStamp(doc *Document) {
+	if !doc.IsPaid() {
+		return
+	}
+
+	currentX, currentY := md.pdf.GetXY()
+
+	md.pdf.SetFont(md.Options.BoldFont, "B", 28)
+	stampColor := md.getSafeColor(md.Options.PaidStampColor, []int{30, 140, 60})
+	md.pdf.SetTextColor(stampColor[0], stampColor[1], stampColor[2])
+	md.pdf.SetDrawColor(stampColor[0], stampColor[1], stampColor[2])
+	md.pdf.SetLineWidth(1)
+
+	// Draw rotated stamp on the right of the metas
+	md.pdf.TransformBegin()
+	md.pdf.TransformRotate(15, 165, 50)
+	md.pdf.SetXY(140, 43)
+	md.pdf.CellFormat(50, 14, doc.encodeString(md.Options.TextPaidStamp), "1", 0, "C", false, 0, "")
+	md.pdf.TransformEnd()
+
+	// Reset line, font and position
+	md.pdf.SetDrawColor(0, 0, 0)
+	md.pdf.SetLineWidth(0.2)
+	md.pdf.SetFont(md.Options.Font, "", 15)
+	baseTextColor := md.getSafeColor(md.Options.BaseTextColor, []int{35, 35, 35})
+	md.pdf.SetTextColor(baseTextColor[0], baseTextColor[1], baseTextColor[2])
+	md.pdf.SetXY(currentX, currentY)
 }
 
 // appendDescription to document
@@ -216,103 +334,21 @@ func (md *MultiDocument) drawsTableTitles(doc *Document) {
 	md.pdf.SetFillColor(greyColor[0], greyColor[1], greyColor[2])
 	md.pdf.Rect(10, md.pdf.GetY(), 190, 6, "F")
 
-	// Name
-	md.pdf.SetX(ItemColNameOffset)
-	md.pdf.CellFormat(
-		ItemColUnitPriceOffset-ItemColNameOffset,
-		6,
-		doc.encodeString(md.Options.TextItemsNameTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Unit price
-	md.pdf.SetX(ItemColUnitPriceOffset)
-	md.pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		6,
-		doc.encodeString(md.Options.TextItemsUnitCostTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Quantity
-	md.pdf.SetX(ItemColQuantityOffset)
-	md.pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		6,
-		doc.encodeString(md.Options.TextItemsQuantityTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Total HT
-	md.pdf.SetX(ItemColTotalHTOffset)
-	md.pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		6,
-		doc.encodeString(md.Options.TextItemsTotalHTTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Tax
-	// md.pdf.SetX(ItemColTaxOffset)
-	// md.pdf.CellFormat(
-	// 	ItemColDiscountOffset-ItemColTaxOffset,
-	// 	6,
-	// 	doc.encodeString(md.Options.TextItemsTaxTitle),
-	// 	"0",
-	// 	0,
-	// 	"",
-	// 	false,
-	// 	0,
-	// 	"",
-	// )
-
-	// Discount
-	md.pdf.SetX(ItemColDiscountOffset)
-	md.pdf.CellFormat(
-		ItemColTotalTTCOffset-ItemColDiscountOffset,
-		6,
-		doc.encodeString(md.Options.TextItemsDiscountTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// TOTAL TTC
-	md.pdf.SetX(ItemColTotalTTCOffset)
-	md.pdf.CellFormat(
-		190-ItemColTotalTTCOffset,
-		6,
-		doc.encodeString(md.Options.TextItemsTotalTTCTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
+	// Columns
+	for _, column := range doc.itemColumns() {
+		md.pdf.SetX(column.x)
+		md.pdf.CellFormat(
+			column.width,
+			6,
+			doc.encodeString(column.title(md.Options)),
+			"0",
+			0,
+			column.align(),
+			false,
+			0,
+			"",
+		)
+	}
 }
 
 // appendItems to document
@@ -323,26 +359,32 @@ func (md *MultiDocument) appendItems(doc *Document) {
 	md.pdf.SetY(md.pdf.GetY() + 8)
 	md.pdf.SetFont(md.Options.Font, "", 10)
 
-	for i := 0; i < len(doc.Items); i++ {
-		item := doc.Items[i]
-
-		// Check item tax
-		if item.Tax == nil {
-			item.Tax = doc.DefaultTax
+	for _, group := range doc.GroupTotals() {
+		// Group header
+		if len(group.Name) > 0 {
+			doc.appendItemsGroupHeader(group)
 		}
 
-		// Append to pdf
-		item.appendColTo(md.Options, doc)
+		for _, item := range group.lines() {
+			// Append to pdf
+			item.appendColTo(md.Options, doc)
 
-		if md.pdf.GetY() > MaxPageHeight {
-			// Add page
-			md.pdf.AddPage()
-			md.drawsTableTitles(doc)
-			md.pdf.SetFont(md.Options.Font, "", 10)
+			if md.pdf.GetY() > MaxPageHeight {
+				// Add page
+				md.pdf.AddPage()
+				md.drawsTableTitles(doc)
+				md.pdf.SetFont(md.Options.Font, "", 10)
+			}
+
+			md.pdf.SetX(10)
+			md.pdf.SetY(md.pdf.GetY() + 8)
 		}
 
-		md.pdf.SetX(10)
-		md.pdf.SetY(md.pdf.GetY() + 8)
+		// Group subtotal
+		if len(group.Name) > 0 {
+			doc.appendItemsGroupSubtotal(group)
+			md.pdf.SetY(md.pdf.GetY() + 4)
+		}
 	}
 }
 
@@ -417,7 +459,7 @@ func (md *MultiDocument) appendTotal(doc *Document) {
 		md.pdf.SetTextColor(greyTextColor[0], greyTextColor[1], greyTextColor[2])
 
 		var descString bytes.Buffer
-		_, discountAmount := doc.Discount.getDiscount()
+		discountAmount := doc.documentDiscountAmount()
 
 		md.pdf.CellFormat(38, 7.5, doc.encodeString(descString.String()), "0", 0, "TR", false, 0, "")
 
@@ -448,6 +490,16 @@ func (md *MultiDocument) appendTotal(doc *Document) {
 		md.pdf.SetY(md.pdf.GetY() + 10)
 	}
 
+	// Draw before tax adjustments
+	for _, step := range doc.BeforeTaxAdjustments() {
+		md.appendTotalRow(doc, step.Adjustment.title(md.Options), step.Amount)
+	}
+
+	// Draw charges
+	for _, charge := range doc.Charges {
+		md.appendTotalRow(doc, charge.Name, charge._amount)
+	}
+
 	// Draw tax title
 	md.pdf.SetX(120)
 	darkColor = md.getSafeColor(md.Options.DarkBgColor, []int{0, 0, 0})
@@ -472,13 +524,18 @@ func (md *MultiDocument) appendTotal(doc *Document) {
 		"",
 	)
 
-	// Draw total with tax title
+	// Draw after tax adjustments
 	md.pdf.SetY(md.pdf.GetY() + 10)
+	for _, step := range doc.AfterTaxAdjustments() {
+		md.appendTotalRow(doc, step.Adjustment.title(md.Options), step.Amount)
+	}
+
+	// Draw total with tax title
 	md.pdf.SetX(120)
 	darkColor = md.getSafeColor(md.Options.DarkBgColor, []int{0, 0, 0})
 	md.pdf.SetFillColor(darkColor[0], darkColor[1], darkColor[2])
 	md.pdf.Rect(120, md.pdf.GetY(), 40, 10, "F")
-	md.pdf.CellFormat(38, 10, doc.encodeString(md.Options.TextTotalWithTax), "0", 0, "R", false, 0, "")
+	md.pdf.CellFormat(38, 10, doc.encodeString(doc.totalWithTaxTitle()), "0", 0, "R", false, 0, "")
 
 	// Draw total with tax amount
 	md.pdf.SetX(162)
@@ -496,15 +553,86 @@ func (md *MultiDocument) appendTotal(doc *Document) {
 		0,
 		"",
 	)
+
+	// Draw paid and balance due
+	if len(doc.Payments) > 0 {
+		md.pdf.SetY(md.pdf.GetY() + 10)
+		md.appendTotalRow(doc, md.Options.TextTotalAmountPaid, doc.AmountPaid())
+		md.appendTotalRow(doc, md.Options.TextTotalBalanceDue, doc.BalanceDue())
+
+		// Keep Y at the top of the last row, as after the total with tax
+		md.pdf.SetY(md.pdf.GetY() - 10)
+	}
+
+	// Draw tax and total in the secondary currency
+	if doc.ExchangeRate != nil {
+		md.pdf.SetY(md.pdf.GetY() + 10)
+		for _, row := range doc.convertedTotalRows() {
+			md.appendTotalTextRow(doc, row[0], row[1])
+		}
+
+		// Applied rate
+		md.pdf.SetX(120)
+		md.pdf.SetFont(md.Options.Font, "", SmallTextFontSize)
+		greyTextColor := md.getSafeColor(md.Options.GreyTextColor, []int{128, 128, 128})
+		md.pdf.SetTextColor(greyTextColor[0], greyTextColor[1], greyTextColor[2])
+		md.pdf.CellFormat(80, 5, doc.encodeString(doc.exchangeRateMention()), "0", 0, "R", false, 0, "")
+
+		// Reset font
+		md.pdf.SetFont(md.Options.Font, "", LargeTextFontSize)
+		md.pdf.SetTextColor(baseTextColor[0], baseTextColor[1], baseTextColor[2])
+
+		// Keep Y 10 above the bottom of the bloc, as after the total with tax
+		md.pdf.SetY(md.pdf.GetY() - 5)
+	}
+}
+
+// appendTotalRow draws a title and amount row of the total bloc at current Y
+func (md *MultiDocument) appendTotalRow(doc *Document, title string, amount decimal.Decimal) {
+	md.appendTotalTextRow(doc, title, doc.ac.FormatMoneyDecimal(amount))
+}
+
+// appendTotalTextRow draws a title and formatted amount row of the total bloc at current Y
+func (md *MultiDocument) appendTotalTextRow(doc *Document, title string, amount string) {
+	// Draw title
+	md.pdf.SetX(120)
+	darkColor := md.getSafeColor(md.Options.DarkBgColor, []int{0, 0, 0})
+	md.pdf.SetFillColor(darkColor[0], darkColor[1], darkColor[2])
+	md.pdf.Rect(120, md.pdf.GetY(), 40, 10, "F")
+	md.pdf.CellFormat(38, 10, doc.encodeString(title), "0", 0, "R", false, 0, "")
+
+	// Draw amount
+	md.pdf.SetX(162)
+	greyColor := md.getSafeColor(md.Options.GreyBgColor, []int{240, 240, 240})
+	md.pdf.SetFillColor(greyColor[0], greyColor[1], greyColor[2])
+	md.pdf.Rect(160, md.pdf.GetY(), 40, 10, "F")
+	md.pdf.CellFormat(
+		40,
+		10,
+		doc.encodeString(amount),
+		"0",
+		0,
+		"L",
+		false,
+		0,
+		"",
+	)
+
+	md.pdf.SetY(md.pdf.GetY() + 10)
 }
 
 // appendPaymentTerm to document
 func (md *MultiDocument) appendPaymentTerm(doc *Document) {
-	if len(doc.PaymentTerm) > 0 {
+	// Receipts are already paid
+	if doc.Type == Receipt {
+		return
+	}
+
+	if dueDate := doc.DueDate(); !dueDate.IsZero() {
 		paymentTermString := fmt.Sprintf(
 			"%s: %s",
 			doc.encodeString(md.Options.TextPaymentTermTitle),
-			doc.encodeString(doc.PaymentTerm),
+			doc.encodeString(doc.formatDate(dueDate)),
 		)
 		md.pdf.SetY(md.pdf.GetY() + 15)
 
@@ -514,6 +642,24 @@ func (md *MultiDocument) appendPaymentTerm(doc *Document) {
 	}
 }
 
+// appendNotice to document
+func (md *MultiDocument) appendNotice(doc *Document) {
+	notice := doc.notice()
+	if len(notice) == 0 {
+		return
+	}
+
+	md.pdf.SetY(md.pdf.GetY() + 15)
+	md.pdf.SetX(BaseMargin)
+	md.pdf.SetFont(md.Options.Font, "", SmallTextFontSize)
+	greyTextColor := md.getSafeColor(md.Options.GreyTextColor, []int{128, 128, 128})
+	md.pdf.SetTextColor(greyTextColor[0], greyTextColor[1], greyTextColor[2])
+	md.pdf.MultiCell(190, 4, doc.encodeString(notice), "0", "C", false)
+
+	baseTextColor := md.getSafeColor(md.Options.BaseTextColor, []int{35, 35, 35})
+	md.pdf.SetTextColor(baseTextColor[0], baseTextColor[1], baseTextColor[2])
+}
+
 // generateBarcode generates a Code 128 barcode image
 func (md *MultiDocument) generateBarcode(content string) ([]byte, error) {
 	if len(content) == 0 {
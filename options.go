@@ -7,38 +7,119 @@ type UnicodeTranslateFunc func(string) string
 type Options struct {
 	AutoPrint bool `json:"auto_print,omitempty"`
 
-	CurrencySymbol       string `default:"€ " json:"currency_symbol,omitempty"`
-	CurrencyPrecision    int    `default:"0" json:"currency_precision,omitempty"`
-	CurrencyDecimal      string `default:"." json:"currency_decimal,omitempty"`
-	CurrencyThousand     string `default:" " json:"currency_thousand,omitempty"`
-	Format               string `default:"%s %v" json:"format,omitempty"`
-	FormatNegative       string `default:"%s -%v" json:"format_negative,omitempty"`
-	FormatZero           string `default:"%s 0" json:"format_zero,omitempty"`
-	BarCode              string `default:"" json:"barcode,omitempty"`
-	TextTypeInvoice      string `default:"INVOICE" json:"text_type_invoice,omitempty"`
-	TextTypeQuotation    string `default:"QUOTATION" json:"text_type_quotation,omitempty"`
-	TextTypeDeliveryNote string `default:"DELIVERY NOTE" json:"text_type_delivery_note,omitempty"`
-	TextPhoneTitle       string `json:"text_phone_title,omitempty" default:"Phone"`
-
-	TextRefTitle         string `default:"Ref." json:"text_ref_title,omitempty"`
-	TextVersionTitle     string `default:"Version" json:"text_version_title,omitempty"`
-	TextDateTitle        string `default:"Date" json:"text_date_title,omitempty"`
-	TextPaymentTermTitle string `default:"Payment term" json:"text_payment_term_title,omitempty"`
-
-	TextItemsNameTitle     string `default:"Name" json:"text_items_name_title,omitempty"`
-	TextItemsUnitCostTitle string `default:"Unit price" json:"text_items_unit_cost_title,omitempty"`
-	TextItemsQuantityTitle string `default:"Qty" json:"text_items_quantity_title,omitempty"`
-	TextItemsTotalHTTitle  string `default:"Total no tax" json:"text_items_total_ht_title,omitempty"`
-	TextItemsTaxTitle      string `default:"Tax" json:"text_items_tax_title,omitempty"`
-	TextItemsDiscountTitle string `default:"Discount" json:"text_items_discount_title,omitempty"`
-	TextItemsTotalTTCTitle string `default:"Total" json:"text_items_total_ttc_title,omitempty"`
+	CurrencySymbol    string `json:"currency_symbol,omitempty"`                      // Document currency symbol, € without currency, when empty
+	CurrencyPrecision int    `json:"currency_precision,omitempty" validate:"min=-1"` // Currency table minor units when 0, NoMinorUnits for none
+	CurrencyDecimal   string `default:"." json:"currency_decimal,omitempty"`
+	CurrencyThousand  string `default:" " json:"currency_thousand,omitempty"`
+	Format            string `json:"format,omitempty"`          // Currency table format, %s %v without currency, when empty
+	FormatNegative    string `json:"format_negative,omitempty"` // Currency table format, %s -%v without currency, when empty
+	FormatZero        string `json:"format_zero,omitempty"`     // Currency table format, %s 0 without currency, when empty
+	RoundingMode      string `default:"half_up" json:"rounding_mode,omitempty" validate:"omitempty,oneof=half_up half_even"`
+	RoundingLevel     string `default:"line" json:"rounding_level,omitempty" validate:"omitempty,oneof=line document"`
+	BarCode           string `default:"" json:"barcode,omitempty"`
+
+	DateFormat      string   `default:"02/01/2006" json:"date_format,omitempty"` // Go time layout
+	MonthNames      []string `json:"month_names,omitempty"`                      // Localized full month names for the January layout token, January first
+	MonthShortNames []string `json:"month_short_names,omitempty"`                // Localized short month names for the Jan layout token, January first
+	DayNames        []string `json:"day_names,omitempty"`                        // Localized full day names for the Monday layout token, Sunday first
+	DayShortNames   []string `json:"day_short_names,omitempty"`                  // Localized short day names for the Mon layout token, Sunday first
+
+	TextTypeInvoice       string `default:"INVOICE" json:"text_type_invoice,omitempty"`
+	TextTypeQuotation     string `default:"QUOTATION" json:"text_type_quotation,omitempty"`
+	TextTypeDeliveryNote  string `default:"DELIVERY NOTE" json:"text_type_delivery_note,omitempty"`
+	TextTypeCreditNote    string `default:"CREDIT NOTE" json:"text_type_credit_note,omitempty"`
+	TextTypeProForma      string `default:"PRO FORMA INVOICE" json:"text_type_pro_forma,omitempty"`
+	TextTypeReceipt       string `default:"RECEIPT" json:"text_type_receipt,omitempty"`
+	TextTypePurchaseOrder string `default:"PURCHASE ORDER" json:"text_type_purchase_order,omitempty"`
+	TextPhoneTitle        string `json:"text_phone_title,omitempty" default:"Phone"`
+	TextTaxIDTitle        string `json:"text_tax_id_title,omitempty" default:"VAT number"`
+
+	TextRefTitle           string `default:"Ref." json:"text_ref_title,omitempty"`
+	TextVersionTitle       string `default:"Version" json:"text_version_title,omitempty"`
+	TextDateTitle          string `default:"Date" json:"text_date_title,omitempty"`
+	TextValidityDateTitle  string `default:"Valid until" json:"text_validity_date_title,omitempty"`
+	TextPaymentTermTitle   string `default:"Payment term" json:"text_payment_term_title,omitempty"`
+	TextTermsNetDays       string `default:"Payment due within %d days, by %s." json:"text_terms_net_days,omitempty"`
+	TextTermsEarlyDiscount string `default:"A %s%% discount (%s) applies if paid by %s." json:"text_terms_early_discount,omitempty"`
+	TextTermsLateFee       string `default:"Late payments bear interest at a yearly rate of %s%%." json:"text_terms_late_fee,omitempty"`
+	TextTermsRecoveryFee   string `default:"A fixed recovery fee of %s is due for any late payment." json:"text_terms_recovery_fee,omitempty"`
+	TextOriginalRefTitle   string `default:"Original invoice" json:"text_original_ref_title,omitempty"`
+	TextPaymentDateTitle   string `default:"Paid on" json:"text_payment_date_title,omitempty"`
+	TextProFormaNotice     string `default:"This pro forma invoice is not a tax invoice and cannot be used to claim tax." json:"text_pro_forma_notice,omitempty"`
+	TextCreditNoteNotice   string `default:"All amounts of this credit note are credited to the customer." json:"text_credit_note_notice,omitempty"`
+
+	TextItemsNameTitle      string `default:"Name" json:"text_items_name_title,omitempty"`
+	TextItemsUnitCostTitle  string `default:"Unit price" json:"text_items_unit_cost_title,omitempty"`
+	TextItemsQuantityTitle  string `default:"Qty" json:"text_items_quantity_title,omitempty"`
+	TextItemsTotalHTTitle   string `default:"Total no tax" json:"text_items_total_ht_title,omitempty"`
+	TextItemsTaxTitle       string `default:"Tax" json:"text_items_tax_title,omitempty"`
+	TextItemsDiscountTitle  string `default:"Discount" json:"text_items_discount_title,omitempty"`
+	TextItemsTotalTTCTitle  string `default:"Total" json:"text_items_total_ttc_title,omitempty"`
+	TextItemsUnitTitle      string `default:"Unit" json:"text_items_unit_title,omitempty"`
+	TextItemsSKUTitle       string `default:"SKU" json:"text_items_sku_title,omitempty"`
+	TextItemsGTINTitle      string `default:"GTIN" json:"text_items_gtin_title,omitempty"`
+	TextItemsHSCodeTitle    string `default:"HS code" json:"text_items_hs_code_title,omitempty"`
+	TextItemsGroupSubtotal  string `default:"Subtotal" json:"text_items_group_subtotal,omitempty"`
+	TextPricingIncluded     string `default:"Included" json:"text_pricing_included,omitempty"`
+	TextItemsDeliveredTitle string `default:"Delivered / Backordered" json:"text_items_delivered_title,omitempty"`
+
+	TextTimesheetTitle       string `default:"TIMESHEET" json:"text_timesheet_title,omitempty"`
+	TextTimesheetDateTitle   string `default:"Date" json:"text_timesheet_date_title,omitempty"`
+	TextTimesheetPersonTitle string `default:"Person" json:"text_timesheet_person_title,omitempty"`
+	TextTimesheetTaskTitle   string `default:"Task" json:"text_timesheet_task_title,omitempty"`
+	TextTimesheetHoursTitle  string `default:"Hours" json:"text_timesheet_hours_title,omitempty"`
+	TextTimesheetRateTitle   string `default:"Rate" json:"text_timesheet_rate_title,omitempty"`
+	TextTimesheetAmountTitle string `default:"Amount" json:"text_timesheet_amount_title,omitempty"`
+	TextTimesheetTotalTitle  string `default:"Total" json:"text_timesheet_total_title,omitempty"`
+	TextTimesheetHoursUnit   string `default:"h" json:"text_timesheet_hours_unit,omitempty"`
+
+	TextSignatureTitle     string `default:"RECEIVED BY" json:"text_signature_title,omitempty"`
+	TextSignatureName      string `default:"Name" json:"text_signature_name,omitempty"`
+	TextSignatureDate      string `default:"Date" json:"text_signature_date,omitempty"`
+	TextSignatureSignature string `default:"Signature" json:"text_signature_signature,omitempty"`
 
 	TextTotalTotal      string `default:"TOTAL" json:"text_total_total,omitempty"`
 	TextTotalDiscounted string `default:"TOTAL DISCOUNTED" json:"text_total_discounted,omitempty"`
+	TextTotalDiscount   string `default:"DISCOUNT" json:"text_total_discount,omitempty"`
+	TextTotalSurcharge  string `default:"SURCHARGE" json:"text_total_surcharge,omitempty"`
 	TextTotalTax        string `default:"TAX" json:"text_total_tax,omitempty"`
 	TextTotalWithTax    string `default:"TOTAL WITH TAX" json:"text_total_with_tax,omitempty"`
+	TextTotalCredit     string `default:"TOTAL CREDIT" json:"text_total_credit,omitempty"`
+	TextTotalPaid       string `default:"TOTAL PAID" json:"text_total_paid,omitempty"`
+	TextTotalAmountPaid string `default:"PAID" json:"text_total_amount_paid,omitempty"`
+	TextTotalBalanceDue string `default:"BALANCE DUE" json:"text_total_balance_due,omitempty"`
+	TextExchangeRate    string `default:"Exchange rate" json:"text_exchange_rate,omitempty"`
 	TextInvoiceTitle    string `default:"INVOICE" json:"text_invoice_title,omitempty"`
 
+	ItemColumns          []*ItemColumn `json:"item_columns,omitempty"`   // Items table columns, default columns when empty
+	ShowItemsTax         bool          `json:"show_items_tax,omitempty"` // Display the per line tax column
+	ShowTaxSummary       bool          `json:"show_tax_summary,omitempty"`
+	TextTaxSummaryRate   string        `default:"Tax rate" json:"text_tax_summary_rate,omitempty"`
+	TextTaxSummaryBase   string        `default:"Taxable base" json:"text_tax_summary_base,omitempty"`
+	TextTaxSummaryTax    string        `default:"Tax" json:"text_tax_summary_tax,omitempty"`
+	TextTaxSummaryAmount string        `default:"Fixed" json:"text_tax_summary_amount,omitempty"`
+
+	TextTaxCategoryZeroRated     string `default:"Zero rated" json:"text_tax_category_zero_rated,omitempty"`
+	TextTaxCategoryExempt        string `default:"Exempt" json:"text_tax_category_exempt,omitempty"`
+	TextTaxCategoryReverseCharge string `default:"Reverse charge" json:"text_tax_category_reverse_charge,omitempty"`
+	TextZeroRatedMention         string `default:"Zero rated supply." json:"text_zero_rated_mention,omitempty"`
+	TextExemptMention            string `default:"VAT exempt supply." json:"text_exempt_mention,omitempty"`
+	TextReverseChargeMention     string `default:"Reverse charge — Article 196 Directive 2006/112/EC." json:"text_reverse_charge_mention,omitempty"`
+	TextCustomerTaxIDTitle       string `default:"Customer VAT number" json:"text_customer_tax_id_title,omitempty"`
+
+	ExpiredWatermark      bool   `json:"expired_watermark,omitempty"`
+	TextExpiredWatermark  string `default:"EXPIRED" json:"text_expired_watermark,omitempty"`
+	ExpiredWatermarkColor []int  `default:"[200,30,30]" json:"expired_watermark_color,omitempty"`
+
+	TextPaidStamp  string `default:"PAID" json:"text_paid_stamp,omitempty"`
+	PaidStampColor []int  `default:"[30,140,60]" json:"paid_stamp_color,omitempty"`
+
+	ShowPaymentsHistory        bool   `json:"show_payments_history,omitempty"`
+	TextPaymentsDateTitle      string `default:"Payment date" json:"text_payments_date_title,omitempty"`
+	TextPaymentsMethodTitle    string `default:"Method" json:"text_payments_method_title,omitempty"`
+	TextPaymentsReferenceTitle string `default:"Reference" json:"text_payments_reference_title,omitempty"`
+	TextPaymentsAmountTitle    string `default:"Amount" json:"text_payments_amount_title,omitempty"`
+
 	BaseTextColor []int `default:"[35,35,35]" json:"base_text_color,omitempty"`
 	GreyTextColor []int `default:"[82,82,82]" json:"grey_text_color,omitempty"`
 	GreyBgColor   []int `default:"[232,232,232]" json:"grey_bg_color,omitempty"`
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidPayment when the payment amount is empty or negative
+var ErrInvalidPayment = errors.New("invalid payment")
+
+// Payment define an amount already received for the document, ex a deposit
+type Payment struct {
+	Date      time.Time `json:"date,omitzero"`
+	Method    string    `json:"method,omitempty"`    // ex Bank transfer
+	Amount    string    `json:"amount,omitempty"`    // Amount received ex 250
+	Reference string    `json:"reference,omitempty"` // ex transaction ID
+
+	_amount decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (p *Payment) Prepare() error {
+	if len(p.Amount) == 0 {
+		return ErrInvalidPayment
+	}
+
+	amount, err := decimal.NewFromString(p.Amount)
+	if err != nil {
+		return err
+	}
+	if amount.IsNegative() {
+		return ErrInvalidPayment
+	}
+	p._amount = amount
+
+	return nil
+}
+
+// AmountPaid return the sum of the recorded payments
+func (doc *Document) AmountPaid() decimal.Decimal {
+	paid := decimal.NewFromFloat(0)
+
+	for _, payment := range doc.Payments {
+		paid = paid.Add(payment._amount)
+	}
+
+	return paid
+}
+
+// BalanceDue return the total with tax minus the recorded payments
+func (doc *Document) BalanceDue() decimal.Decimal {
+	return doc.TotalWithTax().Sub(doc.AmountPaid())
+}
+
+// IsPaid return true if payments were recorded and the balance is settled
+func (doc *Document) IsPaid() bool {
+	return len(doc.Payments) > 0 && doc.BalanceDue().Sign() <= 0
+}
+
+// appendPayments draws the paid and balance due rows under the total with tax
+func (doc *Document) appendPayments() {
+	if len(doc.Payments) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.appendTotalRow(doc.Options.TextTotalAmountPaid, doc.AmountPaid())
+	doc.appendTotalRow(doc.Options.TextTotalBalanceDue, doc.BalanceDue())
+
+	// Keep Y at the top of the last row, as after the total with tax
+	doc.pdf.SetY(doc.pdf.GetY() - 10)
+}
+
+// appendPaymentsHistory draws the table of recorded payments
+func (doc *Document) appendPaymentsHistory() {
+	if !doc.Options.ShowPaymentsHistory || len(doc.Payments) == 0 {
+		return
+	}
+
+	// Check page height (header + one row per payment)
+	if doc.pdf.GetY()+16+float64(len(doc.Payments)+1)*6 > MaxPageHeight {
+		doc.pdf.AddPage()
+	} else {
+		doc.pdf.SetY(doc.pdf.GetY() + 16)
+	}
+
+	doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+
+	// Draw titles
+	doc.pdf.SetX(100)
+	doc.pdf.SetFillColor(doc.Options.DarkBgColor[0], doc.Options.DarkBgColor[1], doc.Options.DarkBgColor[2])
+	doc.pdf.Rect(100, doc.pdf.GetY(), 100, 6, "F")
+	doc.pdf.CellFormat(22, 6, doc.encodeString(doc.Options.TextPaymentsDateTitle), "0", 0, "L", false, 0, "")
+	doc.pdf.CellFormat(26, 6, doc.encodeString(doc.Options.TextPaymentsMethodTitle), "0", 0, "L", false, 0, "")
+	doc.pdf.CellFormat(26, 6, doc.encodeString(doc.Options.TextPaymentsReferenceTitle), "0", 0, "L", false, 0, "")
+	doc.pdf.CellFormat(26, 6, doc.encodeString(doc.Options.TextPaymentsAmountTitle), "0", 0, "R", false, 0, "")
+
+	// Draw payments
+	doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+	for _, payment := range doc.Payments {
+		date := ""
+		if !payment.Date.IsZero() {
+			date = doc.formatDate(payment.Date)
+		}
+
+		doc.pdf.SetXY(100, doc.pdf.GetY()+6)
+		doc.pdf.Rect(100, doc.pdf.GetY(), 100, 6, "F")
+		doc.pdf.CellFormat(22, 6, doc.encodeString(date), "0", 0, "L", false, 0, "")
+		doc.pdf.CellFormat(26, 6, doc.encodeString(payment.Method), "0", 0, "L", false, 0, "")
+		doc.pdf.CellFormat(26, 6, doc.encodeString(payment.Reference), "0", 0, "L", false, 0, "")
+		doc.pdf.CellFormat(26, 6, doc.encodeString(doc.ac.FormatMoneyDecimal(payment._amount)), "0", 0, "R", false, 0, "")
+	}
+}
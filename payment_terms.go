@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidPaymentTerms when a payment terms value is negative or the early payment window exceeds the net days
+var ErrInvalidPaymentTerms = errors.New("invalid payment terms")
+
+// PaymentTerms define when a document must be paid and what happens if it is paid early or late
+// ex: "2/10 net 30" is NetDays 30, DiscountPercent 2 and DiscountDays 10
+type PaymentTerms struct {
+	NetDays         int    `json:"net_days,omitempty" validate:"min=0"` // Days from the document date
+	DiscountPercent string `json:"discount_percent,omitempty"`          // Early payment discount in percent ex 2
+	DiscountDays    int    `json:"discount_days,omitempty" validate:"min=0"`
+	LateFeeRate     string `json:"late_fee_rate,omitempty"` // Yearly late payment interest in percent ex 10.5
+	RecoveryFee     string `json:"recovery_fee,omitempty"`  // Fixed late payment recovery fee ex 40
+
+	_discountPercent decimal.Decimal
+	_lateFeeRate     decimal.Decimal
+	_recoveryFee     decimal.Decimal
+}
+
+// Prepare convert strings to decimal
+func (pt *PaymentTerms) Prepare() error {
+	if pt.NetDays < 0 || pt.DiscountDays < 0 {
+		return ErrInvalidPaymentTerms
+	}
+
+	if pt.NetDays > 0 && pt.DiscountDays > pt.NetDays {
+		return ErrInvalidPaymentTerms
+	}
+
+	// Discount percent
+	if len(pt.DiscountPercent) > 0 {
+		discountPercent, err := decimal.NewFromString(pt.DiscountPercent)
+		if err != nil {
+			return err
+		}
+		if discountPercent.IsNegative() {
+			return ErrInvalidPaymentTerms
+		}
+		pt._discountPercent = discountPercent
+	}
+
+	// Late fee rate
+	if len(pt.LateFeeRate) > 0 {
+		lateFeeRate, err := decimal.NewFromString(pt.LateFeeRate)
+		if err != nil {
+			return err
+		}
+		if lateFeeRate.IsNegative() {
+			return ErrInvalidPaymentTerms
+		}
+		pt._lateFeeRate = lateFeeRate
+	}
+
+	// Recovery fee
+	if len(pt.RecoveryFee) > 0 {
+		recoveryFee, err := decimal.NewFromString(pt.RecoveryFee)
+		if err != nil {
+			return err
+		}
+		if recoveryFee.IsNegative() {
+			return ErrInvalidPaymentTerms
+		}
+		pt._recoveryFee = recoveryFee
+	}
+
+	return nil
+}
+
+// hasEarlyPaymentDiscount return true if an early payment discount is offered
+func (pt *PaymentTerms) hasEarlyPaymentDiscount() bool {
+	return len(pt.DiscountPercent) > 0 && pt.DiscountDays > 0
+}
+
+// EarlyPaymentDate return the last day the early payment discount applies
+func (doc *Document) EarlyPaymentDate() time.Time {
+	if doc.PaymentTerms == nil || !doc.PaymentTerms.hasEarlyPaymentDiscount() {
+		return time.Time{}
+	}
+
+	return doc.date().AddDate(0, 0, doc.PaymentTerms.DiscountDays)
+}
+
+// EarlyPaymentDiscount return the amount deduced from the total with tax when paid early
+func (doc *Document) EarlyPaymentDiscount() decimal.Decimal {
+	if doc.PaymentTerms == nil || !doc.PaymentTerms.hasEarlyPaymentDiscount() {
+		return decimal.NewFromFloat(0)
+	}
+
+	discount := doc.TotalWithTax().Mul(doc.PaymentTerms._discountPercent).Div(decimal.NewFromFloat(100))
+
+	return doc.rounding().round(discount)
+}
+
+// paymentTermsAsString return the payment terms paragraph displayed under totals
+func (doc *Document) paymentTermsAsString() string {
+	if doc.PaymentTerms == nil {
+		return ""
+	}
+
+	var sentences []string
+
+	// Net days, from the document date as an explicit payment term may be another date
+	if doc.PaymentTerms.NetDays > 0 {
+		sentences = append(sentences, fmt.Sprintf(
+			doc.Options.TextTermsNetDays,
+			doc.PaymentTerms.NetDays,
+			doc.formatDate(doc.date().AddDate(0, 0, doc.PaymentTerms.NetDays)),
+		))
+	}
+
+	// Early payment discount
+	if doc.PaymentTerms.hasEarlyPaymentDiscount() {
+		sentences = append(sentences, fmt.Sprintf(
+			doc.Options.TextTermsEarlyDiscount,
+			doc.PaymentTerms._discountPercent.String(),
+			doc.ac.FormatMoneyDecimal(doc.EarlyPaymentDiscount()),
+			doc.formatDate(doc.EarlyPaymentDate()),
+		))
+	}
+
+	// Late fee
+	if len(doc.PaymentTerms.LateFeeRate) > 0 {
+		sentences = append(sentences, fmt.Sprintf(doc.Options.TextTermsLateFee, doc.PaymentTerms._lateFeeRate.String()))
+	}
+
+	// Recovery fee
+	if len(doc.PaymentTerms.RecoveryFee) > 0 {
+		sentences = append(sentences, fmt.Sprintf(doc.Options.TextTermsRecoveryFee, doc.ac.FormatMoneyDecimal(doc.PaymentTerms._recoveryFee)))
+	}
+
+	return strings.Join(sentences, " ")
+}
+
+// appendPaymentTerms to document
+func (doc *Document) appendPaymentTerms() {
+	// Receipts are already paid
+	if doc.Type == Receipt {
+		return
+	}
+
+	terms := doc.paymentTermsAsString()
+	if len(terms) == 0 {
+		return
+	}
+
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.Font, "", ExtraSmallTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.GreyTextColor[0],
+		doc.Options.GreyTextColor[1],
+		doc.Options.GreyTextColor[2],
+	)
+	doc.pdf.MultiCell(190, 4, doc.encodeString(terms), "0", "R", false)
+
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+}
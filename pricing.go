@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidPricing when a pricing model is unknown, its tiers are not ascending
+// or the item quantity is negative
+var ErrInvalidPricing = errors.New("invalid pricing")
+
+// Pricing models
+const (
+	// PricingModelUnit price every billable unit at the item unit cost
+	PricingModelUnit string = "unit"
+	// PricingModelTiered price the units within each tier at the tier unit cost
+	PricingModelTiered string = "tiered"
+	// PricingModelVolume price all the units at the unit cost of the tier reached by the quantity
+	PricingModelVolume string = "volume"
+)
+
+// PriceTier define the unit cost of the units up to UpTo
+type PriceTier struct {
+	UpTo     string `json:"up_to,omitempty"` // Last unit of the tier, empty for the last tier
+	UnitCost string `json:"unit_cost,omitempty"`
+
+	_upTo     decimal.Decimal
+	_unitCost decimal.Decimal
+}
+
+// Pricing define how the total of a usage based item is computed from its quantity
+// Included units are free, tiers apply to the units above them
+type Pricing struct {
+	Model         string       `json:"model,omitempty"` // unit, tiered or volume, default unit
+	Tiers         []*PriceTier `json:"tiers,omitempty"`
+	IncludedUnits string       `json:"included_units,omitempty"` // Free allowance ex 1000
+
+	_includedUnits decimal.Decimal
+}
+
+// TierCharge represent the units billed in a tier and their amount
+// From and To count all the units of the item, included units first
+type TierCharge struct {
+	From     decimal.Decimal `json:"from"`
+	To       decimal.Decimal `json:"to"`       // Zero for the last tier
+	Included bool            `json:"included"` // Free allowance
+	Quantity decimal.Decimal `json:"quantity"`
+	UnitCost decimal.Decimal `json:"unit_cost"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// Prepare convert strings to decimal
+func (p *Pricing) Prepare() error {
+	if len(p.Model) == 0 {
+		p.Model = PricingModelUnit
+	}
+
+	if p.Model != PricingModelUnit && p.Model != PricingModelTiered && p.Model != PricingModelVolume {
+		return ErrInvalidPricing
+	}
+
+	// Tiered and volume pricing need tiers
+	if p.Model != PricingModelUnit && len(p.Tiers) == 0 {
+		return ErrInvalidPricing
+	}
+
+	// Included units
+	p._includedUnits = decimal.NewFromFloat(0)
+	if len(p.IncludedUnits) > 0 {
+		includedUnits, err := decimal.NewFromString(p.IncludedUnits)
+		if err != nil {
+			return err
+		}
+		if includedUnits.IsNegative() {
+			return ErrInvalidPricing
+		}
+		p._includedUnits = includedUnits
+	}
+
+	// Tiers must be ascending, the last one is unbounded
+	previous := decimal.NewFromFloat(0)
+	for index, tier := range p.Tiers {
+		unitCost, err := decimal.NewFromString(tier.UnitCost)
+		if err != nil {
+			return err
+		}
+		tier._unitCost = unitCost
+
+		if len(tier.UpTo) == 0 {
+			if index != len(p.Tiers)-1 {
+				return ErrInvalidPricing
+			}
+			continue
+		}
+
+		// The last tier prices all remaining units
+		if index == len(p.Tiers)-1 {
+			return ErrInvalidPricing
+		}
+
+		upTo, err := decimal.NewFromString(tier.UpTo)
+		if err != nil {
+			return err
+		}
+		if !upTo.GreaterThan(previous) {
+			return ErrInvalidPricing
+		}
+		tier._upTo = upTo
+		previous = upTo
+	}
+
+	return nil
+}
+
+// bounded return true if the tier has an upper bound
+func (t *PriceTier) bounded() bool {
+	return len(t.UpTo) > 0
+}
+
+// absoluteUpTo return the last unit of the tier counted from the first unit of the item, zero for the last tier
+func (t *PriceTier) absoluteUpTo(offset decimal.Decimal) decimal.Decimal {
+	if !t.bounded() {
+		return decimal.NewFromFloat(0)
+	}
+
+	return offset.Add(t._upTo)
+}
+
+// isTiered return true if the item total derives from price tiers rather than its unit cost
+func (i *Item) isTiered() bool {
+	return i.Pricing != nil && len(i.Pricing.Model) > 0 && i.Pricing.Model != PricingModelUnit
+}
+
+// TierBreakdown return the included units and the units billed per tier
+func (i *Item) TierBreakdown() []*TierCharge {
+	if i.Pricing == nil {
+		return nil
+	}
+
+	var charges []*TierCharge
+	zero := decimal.NewFromFloat(0)
+
+	// Free allowance, tiers start after it
+	billable := i._quantity
+	offset := i.Pricing._includedUnits
+	if i.Pricing._includedUnits.IsPositive() {
+		included := decimal.Min(billable, i.Pricing._includedUnits)
+		billable = billable.Sub(included)
+		charges = append(charges, &TierCharge{
+			From:     zero,
+			To:       i.Pricing._includedUnits,
+			Included: true,
+			Quantity: included,
+			UnitCost: zero,
+			Amount:   zero,
+		})
+	}
+
+	switch i.Pricing.Model {
+	case PricingModelTiered:
+		from := zero
+		for _, tier := range i.Pricing.Tiers {
+			if !billable.GreaterThan(from) {
+				break
+			}
+
+			to := billable
+			if tier.bounded() {
+				to = decimal.Min(billable, tier._upTo)
+			}
+
+			quantity := to.Sub(from)
+			charges = append(charges, &TierCharge{
+				From:     offset.Add(from),
+				To:       tier.absoluteUpTo(offset),
+				Quantity: quantity,
+				UnitCost: tier._unitCost,
+				Amount:   quantity.Mul(tier._unitCost),
+			})
+			from = to
+		}
+	case PricingModelVolume:
+		from := zero
+		for _, tier := range i.Pricing.Tiers {
+			if !tier.bounded() || billable.LessThanOrEqual(tier._upTo) {
+				charges = append(charges, &TierCharge{
+					From:     offset.Add(from),
+					To:       tier.absoluteUpTo(offset),
+					Quantity: billable,
+					UnitCost: tier._unitCost,
+					Amount:   billable.Mul(tier._unitCost),
+				})
+				break
+			}
+			from = tier._upTo
+		}
+	default:
+		charges = append(charges, &TierCharge{
+			From:     offset,
+			To:       zero,
+			Quantity: billable,
+			UnitCost: i._unitCost,
+			Amount:   billable.Mul(i._unitCost),
+		})
+	}
+
+	return charges
+}
+
+// meteredAmount return the sum of the tier charges
+func (i *Item) meteredAmount() decimal.Decimal {
+	amount := decimal.NewFromFloat(0)
+	for _, charge := range i.TierBreakdown() {
+		amount = amount.Add(charge.Amount)
+	}
+
+	return amount
+}
+
+// tierBreakdownLines return the tier breakdown displayed under the item name
+func (i *Item) tierBreakdownLines(doc *Document) []string {
+	// Unit pricing without allowance is the usual unit cost * quantity
+	if i.Pricing == nil || (!i.isTiered() && i.Pricing._includedUnits.IsZero()) {
+		return nil
+	}
+
+	var lines []string
+
+	for _, charge := range i.TierBreakdown() {
+		if charge.Included {
+			lines = append(lines, fmt.Sprintf("%s: %s", doc.Options.TextPricingIncluded, charge.Quantity.String()))
+			continue
+		}
+
+		if !i.isTiered() {
+			lines = append(lines, fmt.Sprintf(
+				"%s x %s = %s",
+				charge.Quantity.String(),
+				doc.ac.FormatMoneyDecimal(charge.UnitCost),
+				doc.ac.FormatMoneyDecimal(charge.Amount),
+			))
+			continue
+		}
+
+		tierRange := fmt.Sprintf("%s - %s", charge.From.String(), charge.To.String())
+		if charge.To.IsZero() {
+			tierRange = fmt.Sprintf("%s +", charge.From.String())
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"%s: %s x %s = %s",
+			tierRange,
+			charge.Quantity.String(),
+			doc.ac.FormatMoneyDecimal(charge.UnitCost),
+			doc.ac.FormatMoneyDecimal(charge.Amount),
+		))
+	}
+
+	return lines
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Rounding modes
+const (
+	RoundingHalfUp   string = "half_up"
+	RoundingHalfEven string = "half_even"
+)
+
+// Rounding levels
+const (
+	// RoundingLevelLine round every line amount, document totals are sums of rounded lines
+	RoundingLevelLine string = "line"
+
+	// RoundingLevelDocument keep full precision on lines and only round document totals
+	RoundingLevelDocument string = "document"
+)
+
+// rounding define how monetary amounts are rounded in computations
+type rounding struct {
+	precision int32
+	mode      string
+	level     string
+}
+
+// round amount to the currency minor units
+// A nil rounding keeps the full precision
+func (r *rounding) round(amount decimal.Decimal) decimal.Decimal {
+	if r == nil {
+		return amount
+	}
+
+	if r.mode == RoundingHalfEven {
+		return amount.RoundBank(r.precision)
+	}
+
+	return amount.Round(r.precision)
+}
+
+// roundLine round a line amount when rounding per line
+func (r *rounding) roundLine(amount decimal.Decimal) decimal.Decimal {
+	if r == nil || r.level == RoundingLevelDocument {
+		return amount
+	}
+
+	return r.round(amount)
+}
+
+// rounding return the rounding used by document computations, at the precision of the money format
+func (doc *Document) rounding() *rounding {
+	return &rounding{
+		precision: int32(doc.ac.Precision),
+		mode:      doc.Options.RoundingMode,
+		level:     doc.Options.RoundingLevel,
+	}
+}
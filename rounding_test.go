@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// newRoundingTestDocument return a validated document with amounts that do not round evenly
+func newRoundingTestDocument(t *testing.T, options *Options) *Document {
+	doc := newTestDocument(t, Invoice, options)
+
+	doc.AppendItem(&Item{Name: "A", UnitCost: "1.005", Quantity: "3", Tax: &Tax{Percent: "19.6"}})
+	doc.AppendItem(&Item{Name: "B", UnitCost: "0.333", Quantity: "7", Tax: &Tax{Percent: "5.5"}})
+	doc.AppendItem(&Item{Name: "C", UnitCost: "12.49", Quantity: "0.75", Tax: &Tax{Percent: "19.6"}})
+	doc.AppendItem(&Item{Name: "D", UnitCost: "2.675", Quantity: "1", Discount: &Discount{Percent: "33"}, Tax: &Tax{Percent: "7"}})
+	doc.AppendItem(&Item{
+		Name:     "E",
+		UnitCost: "3.99",
+		Quantity: "3",
+		Taxes:    []*Tax{{Percent: "5"}, {Percent: "9.975", Compound: true}},
+	})
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	return doc
+}
+
+// displayed return amount as printed on the document
+func displayed(doc *Document, amount decimal.Decimal) string {
+	return amount.StringFixed(int32(doc.ac.Precision))
+}
+
+func TestRoundingPerLineLinesSumToTotals(t *testing.T) {
+	for _, mode := range []string{RoundingHalfUp, RoundingHalfEven} {
+		doc := newRoundingTestDocument(t, &Options{
+			CurrencyPrecision: 2,
+			RoundingMode:      mode,
+			RoundingLevel:     RoundingLevelLine,
+		})
+
+		linesWithoutTax := decimal.NewFromFloat(0)
+		linesTax := decimal.NewFromFloat(0)
+		linesWithTax := decimal.NewFromFloat(0)
+
+		for _, item := range doc.Items {
+			// Computed line amounts must already be rounded
+			if !item.TotalWithTaxAndDiscount().Equal(item.TotalWithTaxAndDiscount().Round(2)) {
+				t.Fatalf("%s: line total %s is not rounded", mode, item.TotalWithTaxAndDiscount())
+			}
+
+			linesWithoutTax = linesWithoutTax.Add(decimal.RequireFromString(displayed(doc, item.TotalWithoutTaxAndWithDiscount())))
+			linesTax = linesTax.Add(decimal.RequireFromString(displayed(doc, item.TaxWithTotalDiscounted())))
+			linesWithTax = linesWithTax.Add(decimal.RequireFromString(displayed(doc, item.TotalWithTaxAndDiscount())))
+		}
+
+		if displayed(doc, linesWithoutTax) != displayed(doc, doc.TotalWithoutTax()) {
+			t.Errorf("%s: lines without tax sum to %s, total without tax is %s", mode, linesWithoutTax, doc.TotalWithoutTax())
+		}
+
+		if displayed(doc, linesTax) != displayed(doc, doc.Tax()) {
+			t.Errorf("%s: lines tax sum to %s, tax is %s", mode, linesTax, doc.Tax())
+		}
+
+		if displayed(doc, linesWithTax) != displayed(doc, doc.TotalWithTax()) {
+			t.Errorf("%s: lines with tax sum to %s, total with tax is %s", mode, linesWithTax, doc.TotalWithTax())
+		}
+	}
+}
+
+func TestRoundingTaxBreakdownSumsToTax(t *testing.T) {
+	for _, level := range []string{RoundingLevelLine, RoundingLevelDocument} {
+		doc := newRoundingTestDocument(t, &Options{
+			CurrencyPrecision: 2,
+			RoundingLevel:     level,
+		})
+		doc.SetDiscount(&Discount{Percent: "3.3"})
+
+		tax := decimal.NewFromFloat(0)
+		for _, rateTotal := range doc.TaxBreakdown() {
+			if !rateTotal.Tax.Equal(rateTotal.Tax.Round(2)) {
+				t.Errorf("%s: rate tax %s is not rounded", level, rateTotal.Tax)
+			}
+			tax = tax.Add(rateTotal.Tax)
+		}
+
+		if !tax.Equal(doc.Tax()) {
+			t.Errorf("%s: breakdown tax sum to %s, tax is %s", level, tax, doc.Tax())
+		}
+
+		if !doc.TotalWithTax().Equal(doc.TotalWithoutTax().Add(doc.Tax())) {
+			t.Errorf("%s: total with tax %s is not total without tax %s plus tax %s", level, doc.TotalWithTax(), doc.TotalWithoutTax(), doc.Tax())
+		}
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	amount := decimal.RequireFromString("0.125")
+
+	halfUp := &rounding{precision: 2, mode: RoundingHalfUp, level: RoundingLevelLine}
+	if got := halfUp.round(amount).String(); got != "0.13" {
+		t.Errorf("half up: expected 0.13, got %s", got)
+	}
+
+	halfEven := &rounding{precision: 2, mode: RoundingHalfEven, level: RoundingLevelLine}
+	if got := halfEven.round(amount).String(); got != "0.12" {
+		t.Errorf("half even: expected 0.12, got %s", got)
+	}
+
+	perDocument := &rounding{precision: 2, mode: RoundingHalfUp, level: RoundingLevelDocument}
+	if got := perDocument.roundLine(amount).String(); got != "0.125" {
+		t.Errorf("per document: expected line amount to be kept, got %s", got)
+	}
+}
+
+func TestRoundingWithoutCurrency(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		currency string
+		options  *Options
+	}{
+		{"no currency nor precision", "", &Options{}},
+		{"unknown currency", "XXX", &Options{}},
+		{"no minor units", "", &Options{CurrencyPrecision: NoMinorUnits}},
+		{"currency minor units", "JPY", &Options{}},
+	} {
+		doc := newTestDocument(t, Invoice, test.options)
+		doc.SetCurrency(test.currency)
+		for i := 0; i < 3; i++ {
+			doc.AppendItem(&Item{Name: "A", UnitCost: "1.40", Quantity: "1", Tax: &Tax{Percent: "20"}})
+		}
+
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("%s: got error %v", test.name, err)
+		}
+
+		// Amount as displayed, the symbol comes first for these currencies
+		displayed := func(amount decimal.Decimal) decimal.Decimal {
+			return decimal.RequireFromString(strings.TrimPrefix(doc.ac.FormatMoneyDecimal(amount), doc.ac.Symbol+" "))
+		}
+
+		lines, taxes := decimal.Zero, decimal.Zero
+		for _, item := range doc.Items {
+			lines = lines.Add(displayed(item.TotalWithoutTaxAndWithDiscount()))
+			taxes = taxes.Add(displayed(item.TaxWithTotalDiscounted()))
+		}
+
+		if !lines.Equal(displayed(doc.TotalWithoutTax())) || !taxes.Equal(displayed(doc.Tax())) {
+			t.Errorf("%s: expected lines %s + %s tax to sum to the totals, got %s + %s", test.name, lines, taxes, displayed(doc.TotalWithoutTax()), displayed(doc.Tax()))
+		}
+	}
+}
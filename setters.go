@@ -1,5 +1,9 @@
 package generator
 
+import (
+	"time"
+)
+
 // SetType set type of document
 func (d *Document) SetType(docType string) *Document {
 	d.Type = docType
@@ -24,6 +28,18 @@ func (d *Document) SetRef(ref string) *Document {
 	return d
 }
 
+// SetOriginalRef of document, the reference of the invoice credited by a credit note
+func (d *Document) SetOriginalRef(ref string) *Document {
+	d.OriginalRef = ref
+	return d
+}
+
+// SetOriginalDate of document, the date of the invoice credited by a credit note
+func (d *Document) SetOriginalDate(date time.Time) *Document {
+	d.OriginalDate = date
+	return d
+}
+
 // SetVersion of document
 func (d *Document) SetVersion(version string) *Document {
 	d.Version = version
@@ -54,6 +70,12 @@ func (d *Document) SetCustomer(customer *Contact) *Document {
 	return d
 }
 
+// SetSupplier of document, used instead of the customer by purchase orders
+func (d *Document) SetSupplier(supplier *Contact) *Document {
+	d.Supplier = supplier
+	return d
+}
+
 // AppendItem to document items
 func (d *Document) AppendItem(item *Item) *Document {
 	d.Items = append(d.Items, item)
@@ -61,29 +83,106 @@ func (d *Document) AppendItem(item *Item) *Document {
 }
 
 // SetDate of document
-func (d *Document) SetDate(date string) *Document {
+func (d *Document) SetDate(date time.Time) *Document {
 	d.Date = date
 	return d
 }
 
-// SetPaymentTerm of document
-func (d *Document) SetPaymentTerm(term string) *Document {
+// SetValidityDate of document, the date until which a quotation can be accepted
+func (d *Document) SetValidityDate(date time.Time) *Document {
+	d.ValidityDate = date
+	return d
+}
+
+// SetPaymentTerm of document, the payment due date
+func (d *Document) SetPaymentTerm(term time.Time) *Document {
 	d.PaymentTerm = term
 	return d
 }
 
+// SetNetDays of document payment terms, the due date is then computed relative to the document date (ex: net 30)
+func (d *Document) SetNetDays(days int) *Document {
+	if d.PaymentTerms == nil {
+		d.PaymentTerms = &PaymentTerms{}
+	}
+
+	d.PaymentTerms.NetDays = days
+	return d
+}
+
+// SetPaymentTerms of document
+func (d *Document) SetPaymentTerms(terms *PaymentTerms) *Document {
+	d.PaymentTerms = terms
+	return d
+}
+
+// SetPaymentDate of document, the date a receipt was paid
+func (d *Document) SetPaymentDate(date time.Time) *Document {
+	d.PaymentDate = date
+	return d
+}
+
+// SetPaymentMethod of document, the way a receipt was paid
+func (d *Document) SetPaymentMethod(method string) *Document {
+	d.PaymentMethod = method
+	return d
+}
+
 // SetDefaultTax of document
 func (d *Document) SetDefaultTax(tax *Tax) *Document {
 	d.DefaultTax = tax
 	return d
 }
 
+// SetDefaultTaxes of document, additional taxes applied after the default tax
+func (d *Document) SetDefaultTaxes(taxes []*Tax) *Document {
+	d.DefaultTaxes = taxes
+	return d
+}
+
+// SetTaxInclusive of document, items unit costs then include taxes
+func (d *Document) SetTaxInclusive(taxInclusive bool) *Document {
+	d.TaxInclusive = taxInclusive
+	return d
+}
+
 // SetDiscount of document
 func (d *Document) SetDiscount(discount *Discount) *Document {
 	d.Discount = discount
 	return d
 }
 
+// AppendAdjustment to document adjustments
+func (d *Document) AppendAdjustment(adjustment *Adjustment) *Document {
+	d.Adjustments = append(d.Adjustments, adjustment)
+	return d
+}
+
+// AppendCharge to document charges
+func (d *Document) AppendCharge(charge *Charge) *Document {
+	d.Charges = append(d.Charges, charge)
+	return d
+}
+
+// AppendPayment to document payments
+func (d *Document) AppendPayment(payment *Payment) *Document {
+	d.Payments = append(d.Payments, payment)
+	return d
+}
+
+// SetCurrency of document, an ISO 4217 code ex USD, known currencies set the money format
+func (d *Document) SetCurrency(currency string) *Document {
+	d.Currency = currency
+	d.prepareAccounting()
+	return d
+}
+
+// SetExchangeRate of document, used to show the tax and total in a secondary currency
+func (d *Document) SetExchangeRate(rate *ExchangeRate) *Document {
+	d.ExchangeRate = rate
+	return d
+}
+
 // SetBarCode of document
 func (d *Document) SetBarCode(barcode string) *Document {
 	d.BarCode = barcode
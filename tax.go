@@ -2,6 +2,7 @@ package generator
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/shopspring/decimal"
 )
@@ -15,10 +16,24 @@ const (
 	TaxTypePercent string = "percent"
 )
 
+// Tax categories
+const (
+	TaxCategoryStandard      string = "standard"
+	TaxCategoryZeroRated     string = "zero_rated"
+	TaxCategoryExempt        string = "exempt"
+	TaxCategoryReverseCharge string = "reverse_charge"
+)
+
 // Tax define tax as percent or fixed amount
 type Tax struct {
-	Percent string `json:"percent,omitempty"` // Tax in percent ex 17
-	Amount  string `json:"amount,omitempty"`  // Tax in amount ex 123.40
+	Name     string `json:"name,omitempty"`     // Tax name ex GST
+	Percent  string `json:"percent,omitempty"`  // Tax in percent ex 17
+	Amount   string `json:"amount,omitempty"`   // Tax in amount ex 123.40
+	Compound bool   `json:"compound,omitempty"` // Compound taxes apply on the base plus the taxes listed before them
+
+	Category   string `json:"category,omitempty"`    // Tax category, standard when empty
+	ReasonCode string `json:"reason_code,omitempty"` // Exemption reason code ex VATEX-EU-AE
+	ReasonText string `json:"reason_text,omitempty"` // Exemption legal mention, printed on the document
 
 	_percent decimal.Decimal
 	_amount  decimal.Decimal
@@ -26,7 +41,14 @@ type Tax struct {
 
 // Prepare convert strings to decimal
 func (t *Tax) Prepare() error {
-	if len(t.Percent) == 0 && len(t.Amount) == 0 {
+	switch t.Category {
+	case "", TaxCategoryStandard:
+		if len(t.Percent) == 0 && len(t.Amount) == 0 {
+			return ErrInvalidTax
+		}
+	case TaxCategoryZeroRated, TaxCategoryExempt, TaxCategoryReverseCharge:
+		// No tax is charged, a rate can only be given for information
+	default:
 		return ErrInvalidTax
 	}
 
@@ -51,11 +73,58 @@ func (t *Tax) Prepare() error {
 	return nil
 }
 
+// isExempted return true if no tax is charged because of the tax category
+func (t *Tax) isExempted() bool {
+	return t.Category == TaxCategoryZeroRated ||
+		t.Category == TaxCategoryExempt ||
+		t.Category == TaxCategoryReverseCharge
+}
+
+// taxCategoryLabel return the label of a tax category, empty for standard taxes
+func taxCategoryLabel(category string, options *Options) string {
+	switch category {
+	case TaxCategoryZeroRated:
+		return options.TextTaxCategoryZeroRated
+	case TaxCategoryExempt:
+		return options.TextTaxCategoryExempt
+	case TaxCategoryReverseCharge:
+		return options.TextTaxCategoryReverseCharge
+	}
+
+	return ""
+}
+
+// mention return the legal mention of an exempted tax, with its reason code
+func (t *Tax) mention(options *Options) string {
+	mention := t.ReasonText
+
+	if len(mention) == 0 {
+		switch t.Category {
+		case TaxCategoryZeroRated:
+			mention = options.TextZeroRatedMention
+		case TaxCategoryExempt:
+			mention = options.TextExemptMention
+		case TaxCategoryReverseCharge:
+			mention = options.TextReverseChargeMention
+		}
+	}
+
+	if len(t.ReasonCode) > 0 {
+		mention = fmt.Sprintf("%s (%s)", mention, t.ReasonCode)
+	}
+
+	return mention
+}
+
 // getTax return the tax type and value
 func (t *Tax) getTax() (string, decimal.Decimal) {
 	tax := "0"
 	taxType := TaxTypePercent
 
+	if t.isExempted() {
+		return taxType, decimal.NewFromFloat(0)
+	}
+
 	if len(t.Percent) > 0 {
 		tax = t.Percent
 	}
@@ -69,3 +138,42 @@ func (t *Tax) getTax() (string, decimal.Decimal) {
 
 	return taxType, decVal
 }
+
+// appliedTax is a tax computed on a base
+type appliedTax struct {
+	tax    *Tax
+	base   decimal.Decimal
+	amount decimal.Decimal
+}
+
+// applyTaxes compute each tax on base, in order
+// Compound taxes are computed on base plus the amount of the taxes before them
+// Fixed amount taxes are multiplied by ratio, the share of the line kept after the document discount
+// Each tax amount is rounded with r when rounding per line
+func applyTaxes(taxes []*Tax, base decimal.Decimal, ratio decimal.Decimal, r *rounding) []*appliedTax {
+	applied := make([]*appliedTax, 0, len(taxes))
+	cumulated := decimal.NewFromFloat(0)
+
+	for _, tax := range taxes {
+		taxBase := base
+		if tax.Compound {
+			taxBase = base.Add(cumulated)
+		}
+
+		taxType, taxAmount := tax.getTax()
+		// Fixed amounts follow the share of the line left after the document discount
+		amount := r.roundLine(taxAmount.Mul(ratio))
+		if taxType == TaxTypePercent {
+			amount = r.roundLine(taxBase.Mul(taxAmount.Div(decimal.NewFromFloat(100))))
+		}
+
+		cumulated = cumulated.Add(amount)
+		applied = append(applied, &appliedTax{
+			tax:    tax,
+			base:   taxBase,
+			amount: amount,
+		})
+	}
+
+	return applied
+}
@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidTimeEntry when a time entry has no hours or no rate
+var ErrInvalidTimeEntry = errors.New("invalid time entry")
+
+// ErrInvalidHours when a duration is neither a decimal nor a hours:minutes value
+var ErrInvalidHours = errors.New("invalid hours")
+
+// TimeEntry define time spent on a timesheet item
+type TimeEntry struct {
+	Date   time.Time `json:"date,omitzero"`
+	Person string    `json:"person,omitempty"`
+	Hours  string    `json:"hours,omitempty"` // Decimal hours ex 1.5 or duration ex 1:30
+	Rate   string    `json:"rate,omitempty"`  // Hourly rate, the item unit cost when empty
+	Task   string    `json:"task,omitempty"`  // Task reference ex JIRA-123
+
+	_hours decimal.Decimal
+	_rate  decimal.Decimal
+}
+
+// parseHours parse decimal hours ex 1.5 or a hours:minutes duration ex 1:30
+func parseHours(hours string) (decimal.Decimal, error) {
+	parts := strings.Split(strings.TrimSpace(hours), ":")
+
+	switch len(parts) {
+	case 1:
+		value, err := decimal.NewFromString(parts[0])
+		if err != nil || value.IsNegative() {
+			return decimal.Decimal{}, ErrInvalidHours
+		}
+		return value, nil
+	case 2:
+		h, err := strconv.Atoi(parts[0])
+		if err != nil || h < 0 {
+			return decimal.Decimal{}, ErrInvalidHours
+		}
+
+		m, err := strconv.Atoi(parts[1])
+		if err != nil || m < 0 || m > 59 || len(parts[1]) != 2 {
+			return decimal.Decimal{}, ErrInvalidHours
+		}
+
+		return decimal.NewFromInt(int64(h)).Add(decimal.NewFromInt(int64(m)).Div(decimal.NewFromInt(60))), nil
+	}
+
+	return decimal.Decimal{}, ErrInvalidHours
+}
+
+// Prepare convert strings to decimal, rate is the item unit cost when the entry has none
+func (e *TimeEntry) Prepare(rate decimal.Decimal, hasRate bool) error {
+	hours, err := parseHours(e.Hours)
+	if err != nil {
+		return err
+	}
+	e._hours = hours
+
+	// Rate
+	if len(e.Rate) == 0 {
+		if !hasRate {
+			return ErrInvalidTimeEntry
+		}
+		e._rate = rate
+		return nil
+	}
+
+	entryRate, err := decimal.NewFromString(e.Rate)
+	if err != nil {
+		return err
+	}
+	e._rate = entryRate
+
+	return nil
+}
+
+// Amount return the entry hours times its rate
+func (e *TimeEntry) Amount() decimal.Decimal {
+	return e._hours.Mul(e._rate)
+}
+
+// prepareTimeEntries prepare the item time entries and aggregate them on the item line
+// Quantity is the total hours and unit cost the average rate
+func (i *Item) prepareTimeEntries() error {
+	hours := decimal.NewFromFloat(0)
+	amount := decimal.NewFromFloat(0)
+
+	for _, entry := range i.TimeEntries {
+		if err := entry.Prepare(i._unitCost, len(i.UnitCost) > 0); err != nil {
+			return err
+		}
+
+		hours = hours.Add(entry._hours)
+		amount = amount.Add(i.timeEntryAmount(entry))
+	}
+
+	i._quantity = hours
+	if !hours.IsZero() {
+		i._unitCost = amount.Div(hours)
+	}
+
+	return nil
+}
+
+// timeEntryAmount return the entry amount rounded as shown in the timesheet annex
+func (i *Item) timeEntryAmount(entry *TimeEntry) decimal.Decimal {
+	return i._rounding.round(entry.Amount())
+}
+
+// timeEntriesAmount return the sum of the item time entries amounts, as many as the annex rows
+func (i *Item) timeEntriesAmount() decimal.Decimal {
+	amount := decimal.NewFromFloat(0)
+	for _, entry := range i.TimeEntries {
+		amount = amount.Add(i.timeEntryAmount(entry))
+	}
+
+	return amount
+}
+
+// timesheetItems return the document lines with time entries, bundle components included
+func (doc *Document) timesheetItems() []*Item {
+	var items []*Item
+	for _, item := range doc.Items {
+		for _, line := range item.withChildren() {
+			if len(line.TimeEntries) > 0 {
+				items = append(items, line)
+			}
+		}
+	}
+
+	return items
+}
+
+// appendTimesheet draws the detail of time entries as an annex after the document
+func (doc *Document) appendTimesheet() {
+	items := doc.timesheetItems()
+	if len(items) == 0 {
+		return
+	}
+
+	// Check page height (title + header + first entries)
+	if doc.pdf.GetY()+40 > MaxPageHeight {
+		doc.pdf.AddPage()
+	} else {
+		doc.pdf.SetY(doc.pdf.GetY() + 16)
+	}
+
+	// Title
+	doc.pdf.SetX(BaseMargin)
+	doc.pdf.SetFont(doc.Options.BoldFont, "B", LargeTextFontSize)
+	doc.pdf.SetTextColor(
+		doc.Options.BaseTextColor[0],
+		doc.Options.BaseTextColor[1],
+		doc.Options.BaseTextColor[2],
+	)
+	doc.pdf.CellFormat(ItemsTableWidth, 8, doc.encodeString(doc.Options.TextTimesheetTitle), "0", 0, "L", false, 0, "")
+	doc.pdf.SetY(doc.pdf.GetY() + 10)
+
+	// Columns widths: date, person, task, hours, rate, amount
+	widths := []float64{25, 45, 50, 20, 25, 25}
+	titles := []string{
+		doc.Options.TextTimesheetDateTitle,
+		doc.Options.TextTimesheetPersonTitle,
+		doc.Options.TextTimesheetTaskTitle,
+		doc.Options.TextTimesheetHoursTitle,
+		doc.Options.TextTimesheetRateTitle,
+		doc.Options.TextTimesheetAmountTitle,
+	}
+	aligns := []string{"L", "L", "L", "R", "R", "R"}
+
+	// drawRow draws a row of the timesheet table at current Y
+	drawRow := func(values []string) {
+		doc.pdf.SetX(BaseMargin)
+		for index, value := range values {
+			doc.pdf.CellFormat(widths[index], 6, doc.encodeString(value), "0", 0, aligns[index], false, 0, "")
+		}
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+	}
+
+	// drawTitles draws the timesheet table titles
+	drawTitles := func() {
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.SetFillColor(doc.Options.GreyBgColor[0], doc.Options.GreyBgColor[1], doc.Options.GreyBgColor[2])
+		doc.pdf.Rect(BaseMargin, doc.pdf.GetY(), ItemsTableWidth, 6, "F")
+		drawRow(titles)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	}
+
+	drawTitles()
+
+	for _, item := range items {
+		// Item name
+		doc.pdf.SetX(BaseMargin)
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		doc.pdf.CellFormat(ItemsTableWidth, 6, doc.encodeString(item.Name), "B", 0, "L", false, 0, "")
+		doc.pdf.SetY(doc.pdf.GetY() + 6)
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+
+		for _, entry := range item.TimeEntries {
+			if doc.pdf.GetY() > MaxPageHeight {
+				doc.pdf.AddPage()
+				drawTitles()
+			}
+
+			date := ""
+			if !entry.Date.IsZero() {
+				date = doc.formatDate(entry.Date)
+			}
+
+			drawRow([]string{
+				date,
+				entry.Person,
+				entry.Task,
+				entry._hours.StringFixed(2),
+				doc.ac.FormatMoneyDecimal(entry._rate),
+				doc.ac.FormatMoneyDecimal(item.timeEntryAmount(entry)),
+			})
+		}
+
+		// Item total
+		doc.pdf.SetFont(doc.Options.BoldFont, "B", BaseTextFontSize)
+		drawRow([]string{
+			"",
+			"",
+			doc.Options.TextTimesheetTotalTitle,
+			item._quantity.StringFixed(2),
+			"",
+			doc.ac.FormatMoneyDecimal(item.timeEntriesAmount()),
+		})
+		doc.pdf.SetFont(doc.Options.Font, "", BaseTextFontSize)
+	}
+}
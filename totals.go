@@ -1,6 +1,9 @@
 package generator
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -8,78 +11,204 @@ import (
 func (doc *Document) TotalWithoutTaxAndWithoutDocumentDiscount() decimal.Decimal {
 	total := decimal.NewFromInt(0)
 
-	for _, item := range doc.Items {
+	for _, item := range doc.pricedItems() {
 		total = total.Add(item.TotalWithoutTaxAndWithDiscount())
 	}
 
-	return total
+	return doc.rounding().round(total)
 }
 
-// TotalWithoutTax return total without tax and with document discount
+// TotalWithoutTax return total without tax, with document discount and charges
 func (doc *Document) TotalWithoutTax() decimal.Decimal {
 	total := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
 
-	// Apply document discount
-	if doc.Discount != nil {
-		discountType, discountNumber := doc.Discount.getDiscount()
-
-		if discountType == DiscountTypeAmount {
-			total = total.Sub(discountNumber)
-		} else {
-			// Percent
-			toSub := total.Mul(discountNumber.Div(decimal.NewFromFloat(100)))
-			total = total.Sub(toSub)
-		}
-	}
+	// Apply document discount and before tax adjustments
+	total = total.Sub(doc.documentAdjustmentAmount())
 
-	return total
+	// Add charges
+	return total.Add(doc.ChargesTotal())
 }
 
-// TotalWithTax return total with tax and with document discount
+// TotalWithTax return total with tax, with document discount and adjustments
 func (doc *Document) TotalWithTax() decimal.Decimal {
 	totalWithoutTax := doc.TotalWithoutTax()
 	tax := doc.Tax()
 
-	return totalWithoutTax.Add(tax)
+	total := totalWithoutTax.Add(tax)
+	for _, step := range doc.adjustmentSteps(total, true) {
+		total = step.Total
+	}
+
+	return total
 }
 
 // Tax return the total tax with document discount
 func (doc *Document) Tax() decimal.Decimal {
-	totalWithoutTaxAndWithoutDocDiscount := doc.TotalWithoutTaxAndWithoutDocumentDiscount()
 	totalTax := decimal.NewFromFloat(0)
 
-	if doc.Discount == nil {
-		for _, item := range doc.Items {
-			totalTax = totalTax.Add(item.TaxWithTotalDiscounted())
+	for _, rateTotal := range doc.TaxBreakdown() {
+		totalTax = totalTax.Add(rateTotal.Tax)
+	}
+
+	return totalTax
+}
+
+// TaxRateTotal represent the taxable base and the tax amount of a tax rate
+type TaxRateTotal struct {
+	Name     string          `json:"name,omitempty"`
+	Category string          `json:"category,omitempty"` // Tax category, exempted categories have no tax
+	Type     string          `json:"type"`               // TaxTypePercent or TaxTypeAmount
+	Percent  decimal.Decimal `json:"percent"`            // Tax rate, zero for fixed amount taxes
+	Base     decimal.Decimal `json:"base"`               // Total without tax and with document discount, plus previous taxes for compound taxes
+	Tax      decimal.Decimal `json:"tax"`
+}
+
+// TaxBreakdown return the taxable base and tax amount per tax name and rate, sorted by rate
+// Fixed amount taxes are grouped per name, after the rates
+func (doc *Document) TaxBreakdown() []*TaxRateTotal {
+	// An invalid allocation is reported by Validate, no discount is applied then
+	allocation, err := doc.DiscountAllocation()
+	if err != nil {
+		allocation = make([]decimal.Decimal, len(doc.pricedItems()))
+	}
+
+	rateTotals := make(map[string]*TaxRateTotal)
+	r := doc.rounding()
+
+	// rateTotal return the entry of the given tax, creating it if needed
+	rateTotal := func(name string, category string, taxType string, percent decimal.Decimal) *TaxRateTotal {
+		key := fmt.Sprintf("%s|%s|%s|%s", name, category, taxType, percent.String())
+
+		if _, ok := rateTotals[key]; !ok {
+			rateTotals[key] = &TaxRateTotal{
+				Name:     name,
+				Category: category,
+				Type:     taxType,
+				Percent:  percent,
+				Base:     decimal.NewFromFloat(0),
+				Tax:      decimal.NewFromFloat(0),
+			}
 		}
-	} else {
-		discountType, discountAmount := doc.Discount.getDiscount()
-		discountPercent := discountAmount
-		if discountType == DiscountTypeAmount {
-			// Get percent from total discounted
-			discountPercent = discountAmount.Mul(decimal.NewFromFloat(100)).Div(totalWithoutTaxAndWithoutDocDiscount)
+
+		return rateTotals[key]
+	}
+
+	for index, item := range doc.pricedItems() {
+		// Remove allocated doc discount from item total without tax and item discount
+		itemTotalDiscounted := item.TotalWithoutTaxAndWithDiscount().Sub(allocation[index])
+
+		// Untaxed items are accounted at a 0 % rate
+		taxes := item.taxes()
+		if len(taxes) == 0 {
+			total := rateTotal("", "", TaxTypePercent, decimal.NewFromFloat(0))
+			total.Base = total.Base.Add(itemTotalDiscounted)
+			continue
 		}
 
-		for _, item := range doc.Items {
-			if item.Tax != nil {
-				taxType, taxAmount := item.Tax.getTax()
-				if taxType == TaxTypeAmount {
-					// If tax type is amount, just add amount to tax
-					totalTax = totalTax.Add(taxAmount)
-				} else {
-					// Else, remove doc discount % from item total without tax and item discount
-					itemTotal := item.TotalWithoutTaxAndWithDiscount()
-					toSub := discountPercent.Mul(itemTotal).Div(decimal.NewFromFloat(100))
-					itemTotalDiscounted := itemTotal.Sub(toSub)
-
-					// Then recompute tax on itemTotalDiscounted
-					itemTaxDiscounted := taxAmount.Mul(itemTotalDiscounted).Div(decimal.NewFromFloat(100))
-
-					totalTax = totalTax.Add(itemTaxDiscounted)
-				}
+		// Then compute taxes on itemTotalDiscounted
+		for _, applied := range item.appliedTaxes(itemTotalDiscounted) {
+			taxType, taxAmount := applied.tax.getTax()
+			if taxType == TaxTypeAmount {
+				taxAmount = decimal.NewFromFloat(0)
 			}
+
+			total := rateTotal(applied.tax.Name, applied.tax.Category, taxType, taxAmount)
+			total.Base = total.Base.Add(applied.base)
+			total.Tax = total.Tax.Add(applied.amount)
 		}
 	}
 
-	return totalTax
+	for _, charge := range doc.Charges {
+		// Untaxed charges are accounted at a 0 % rate
+		if charge.Tax == nil {
+			total := rateTotal("", "", TaxTypePercent, decimal.NewFromFloat(0))
+			total.Base = total.Base.Add(charge._amount)
+			continue
+		}
+
+		for _, applied := range applyTaxes(charge.taxes(), charge._amount, decimal.NewFromFloat(1), r) {
+			taxType, taxAmount := applied.tax.getTax()
+			if taxType == TaxTypeAmount {
+				taxAmount = decimal.NewFromFloat(0)
+			}
+
+			total := rateTotal(applied.tax.Name, applied.tax.Category, taxType, taxAmount)
+			total.Base = total.Base.Add(applied.base)
+			total.Tax = total.Tax.Add(applied.amount)
+		}
+	}
+
+	// Round totals, a no-op when rounding per line
+	breakdown := make([]*TaxRateTotal, 0, len(rateTotals))
+	for _, rateTotal := range rateTotals {
+		rateTotal.Base = r.round(rateTotal.Base)
+		rateTotal.Tax = r.round(rateTotal.Tax)
+		breakdown = append(breakdown, rateTotal)
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Type != breakdown[j].Type {
+			return breakdown[i].Type == TaxTypePercent
+		}
+		if !breakdown[i].Percent.Equal(breakdown[j].Percent) {
+			return breakdown[i].Percent.LessThan(breakdown[j].Percent)
+		}
+		if breakdown[i].Name != breakdown[j].Name {
+			return breakdown[i].Name < breakdown[j].Name
+		}
+		return breakdown[i].Category < breakdown[j].Category
+	})
+
+	return breakdown
+}
+
+// taxes return the taxes of the document items and charges
+func (doc *Document) taxes() []*Tax {
+	var taxes []*Tax
+
+	for _, item := range doc.pricedItems() {
+		taxes = append(taxes, item.taxes()...)
+	}
+
+	for _, charge := range doc.Charges {
+		taxes = append(taxes, charge.taxes()...)
+	}
+
+	return taxes
+}
+
+// hasTaxCategory return true if an item or a charge of the document has a tax of the given category
+func (doc *Document) hasTaxCategory(category string) bool {
+	for _, tax := range doc.taxes() {
+		if tax.Category == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// taxMentions return the legal mentions of the exempted taxes of the document
+func (doc *Document) taxMentions() []string {
+	var mentions []string
+	seen := make(map[string]bool)
+
+	for _, tax := range doc.taxes() {
+		if !tax.isExempted() {
+			continue
+		}
+
+		mention := tax.mention(doc.Options)
+		if !seen[mention] {
+			seen[mention] = true
+			mentions = append(mentions, mention)
+		}
+	}
+
+	// Reverse charge requires the customer VAT number on the document
+	if doc.hasTaxCategory(TaxCategoryReverseCharge) {
+		mentions = append(mentions, fmt.Sprintf("%s: %s", doc.Options.TextCustomerTaxIDTitle, doc.recipient().TaxID))
+	}
+
+	return mentions
 }
@@ -1,9 +1,20 @@
 package generator
 
 import (
+	"errors"
+
 	"github.com/go-playground/validator/v10"
 )
 
+// ErrInvalidValidityDate when the validity date is before the document date
+var ErrInvalidValidityDate = errors.New("validity date is before document date")
+
+// ErrMissingCustomerTaxID when reverse charge is used and the customer has no tax ID
+var ErrMissingCustomerTaxID = errors.New("missing customer tax id for reverse charge")
+
+// ErrInvalidDueDate when the payment due date is before the document date
+var ErrInvalidDueDate = errors.New("due date is before document date")
+
 // Validate document fields
 func (d *Document) Validate() error {
 	validate := validator.New()
@@ -11,9 +22,60 @@ func (d *Document) Validate() error {
 		return err
 	}
 
+	// Currency may have been set without SetCurrency
+	d.prepareAccounting()
+
+	date := truncateDay(d.date())
+
+	// Check validity date
+	if !d.ValidityDate.IsZero() && d.ValidityDate.Before(date) {
+		return ErrInvalidValidityDate
+	}
+
+	// Check due date
+	if dueDate := d.DueDate(); !dueDate.IsZero() && dueDate.Before(date) {
+		return ErrInvalidDueDate
+	}
+
 	// Prepare items
-	for _, item := range d.Items {
-		if err := item.Prepare(); err != nil {
+	if err := d.prepareItems(d.Items, 0, false); err != nil {
+		return err
+	}
+
+	// Check items table columns
+	if err := validateItemColumns(d.Options.ItemColumns); err != nil {
+		return err
+	}
+
+	// Prepare charges
+	for _, charge := range d.Charges {
+		if err := charge.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare payments
+	for _, payment := range d.Payments {
+		if err := payment.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Prepare exchange rate
+	if err := d.prepareExchangeRate(); err != nil {
+		return err
+	}
+
+	// Reverse charge requires the customer tax ID
+	if d.hasTaxCategory(TaxCategoryReverseCharge) {
+		if recipient := d.recipient(); recipient == nil || len(recipient.TaxID) == 0 {
+			return ErrMissingCustomerTaxID
+		}
+	}
+
+	// Prepare payment terms
+	if d.PaymentTerms != nil {
+		if err := d.PaymentTerms.Prepare(); err != nil {
 			return err
 		}
 	}
@@ -25,5 +87,59 @@ func (d *Document) Validate() error {
 		}
 	}
 
+	// Prepare adjustments
+	for _, adjustment := range d.Adjustments {
+		if err := adjustment.Prepare(); err != nil {
+			return err
+		}
+	}
+
+	// Document discount and adjustments must be allocable to items
+	if _, err := d.DiscountAllocation(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// prepareItems check and prepare items and their bundle components
+func (d *Document) prepareItems(items []*Item, depth int, included bool) error {
+	for _, item := range items {
+		item._depth = depth
+		item._included = included
+
+		// Delivery notes, bundle components priced by their parent and bundles priced by their children carry no unit cost
+		// Timesheet entries may carry their own rate and tiered lines are priced by their tiers
+		if d.Type != DeliveryNote && !included && !item.rollsUp() && len(item.TimeEntries) == 0 && !item.isTiered() && len(item.UnitCost) == 0 {
+			return ErrMissingUnitCost
+		}
+
+		// Timesheet quantities are hours
+		if len(item.TimeEntries) > 0 && len(item.Unit) == 0 {
+			item.Unit = d.Options.TextTimesheetHoursUnit
+		}
+
+		// Check item taxes
+		if item.Tax == nil && len(item.Taxes) == 0 {
+			item.Tax = d.DefaultTax
+			item.Taxes = d.DefaultTaxes
+		}
+
+		// Tax inclusive pricing and rounding
+		if d.TaxInclusive {
+			item.TaxInclusive = true
+		}
+		item._rounding = d.rounding()
+
+		if err := item.Prepare(); err != nil {
+			return err
+		}
+
+		// Bundle components
+		if err := d.prepareItems(item.Children, depth+1, included || !item.rollsUp()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }